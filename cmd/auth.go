@@ -1,13 +1,16 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/longkey1/gotion/internal/gotion"
@@ -24,11 +27,73 @@ const (
 )
 
 type authOptions struct {
-	port int
+	port     int
+	keychain bool
+	saveAs   string
+
+	clientID     string
+	clientSecret string
+
+	noBrowser bool
+	manual    bool
+}
+
+// pasteCode reports whether the authorization code is pasted instead of received by the callback server
+func (o *authOptions) pasteCode() bool {
+	return o.noBrowser || o.manual
 }
 
 var authOpts = &authOptions{}
 
+type authSetTokenOptions struct {
+	verify bool
+}
+
+var authSetTokenOpts = &authSetTokenOptions{}
+
+var authSetTokenCmd = &cobra.Command{
+	Use:   "set-token",
+	Short: "Save an integration token read from stdin",
+	Long: `Read an API integration token from stdin and save it as the API backend
+token, without the OAuth flow. Use this to set up internal integrations,
+which have a static token.
+
+  echo "$NOTION_TOKEN" | gotion auth set-token --verify
+
+An existing token is replaced.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAuthSetToken(cmd.Context(), authSetTokenOpts)
+	},
+}
+
+var authRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "Refresh the access token now",
+	Long: `Refresh the access token using the stored refresh token, even if it has
+not expired yet, and print the new expiry.
+
+Useful before a long batch job. Tokens without a refresh token (e.g. direct
+integration tokens) cannot be refreshed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAuthRefresh(cmd.Context())
+	},
+}
+
+var authMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Upgrade the stored token to the current format",
+	Long: `Rewrite the stored token in the current schema version.
+
+Older tokens are upgraded in memory whenever they are read, and rewritten
+on the next refresh; this command rewrites them now.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runAuthMigrate()
+	},
+}
+
 var authCmd = &cobra.Command{
 	Use:   "auth",
 	Short: "Authenticate with Notion API using OAuth",
@@ -41,14 +106,34 @@ The authentication method is determined by the 'backend' setting in config.toml:
 
 For API backend, configure credentials:
   - Set GOTION_CLIENT_ID and GOTION_CLIENT_SECRET environment variables
-  - Or add client_id and client_secret to ~/.config/gotion/config.toml`,
+  - Or add client_id and client_secret to ~/.config/gotion/config.toml
+  - Or pass --client-id and --client-secret for a one-off run
+
+Use --port to change the callback port of the API backend, or --port 0 to
+pick any free port. The resulting redirect URI must be registered with the
+integration. The MCP backend always uses its registered port.
+
+On machines without a browser (e.g. over SSH), use --no-browser to print the
+authorization URL and paste back the URL you are redirected to, or --manual
+to paste just the code parameter from it. No callback server is started.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return runAuth(cmd.Context(), authOpts)
 	},
 }
 
 func init() {
-	authCmd.Flags().IntVarP(&authOpts.port, "port", "p", defaultCallbackPort, "Local callback server port")
+	authCmd.Flags().IntVarP(&authOpts.port, "port", "p", defaultCallbackPort, "Local callback server port, 0 for any free port (API backend)")
+	authCmd.Flags().BoolVar(&authOpts.keychain, "keychain", false, "Store the token in the OS keychain instead of the token file")
+	authCmd.Flags().StringVar(&authOpts.clientID, "client-id", "", "OAuth client ID, overriding env and config (API backend)")
+	authCmd.Flags().StringVar(&authOpts.clientSecret, "client-secret", "", "OAuth client secret, overriding env and config (API backend)")
+	authCmd.Flags().BoolVar(&authOpts.noBrowser, "no-browser", false, "Print the authorization URL instead of opening a browser, then paste the redirect URL")
+	authCmd.Flags().BoolVar(&authOpts.manual, "manual", false, "Like --no-browser, but paste only the authorization code")
+	authCmd.Flags().StringVar(&authOpts.saveAs, "save-as", "", "Save the token under the named profile (select it later with --profile)")
+	authSetTokenCmd.Flags().BoolVar(&authSetTokenOpts.verify, "verify", false, "Check that the token works before saving it")
+
+	authCmd.AddCommand(authSetTokenCmd)
+	authCmd.AddCommand(authRefreshCmd)
+	authCmd.AddCommand(authMigrateCmd)
 	rootCmd.AddCommand(authCmd)
 }
 
@@ -59,11 +144,45 @@ func runAuth(ctx context.Context, opts *authOptions) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
+	if opts.keychain {
+		config.UseTokenStore(config.TokenStoreKeychain)
+	}
+
+	if opts.pasteCode() && opts.port == 0 {
+		return fmt.Errorf("--port must not be 0 with --no-browser or --manual")
+	}
+
+	if (opts.clientID == "") != (opts.clientSecret == "") {
+		return fmt.Errorf("--client-id and --client-secret must be given together")
+	}
+
+	// OAuth credentials come from the current profile, even with --save-as
+	var oauthCfg *config.Config
+	if cfg.Backend == config.BackendAPI || cfg.Backend == "" {
+		oauthCfg, err = config.LoadOAuthConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load OAuth config: %w", err)
+		}
+		if opts.clientID != "" {
+			fmt.Fprintln(os.Stderr, "Warning: secrets passed on the command line may be saved in shell history.")
+			oauthCfg.ClientID = opts.clientID
+			oauthCfg.ClientSecret = opts.clientSecret
+		}
+	} else if opts.clientID != "" {
+		return fmt.Errorf("--client-id and --client-secret are only used with API backend")
+	}
+
+	// Store the token under the named profile
+	if opts.saveAs != "" {
+		if err := config.SetProfile(opts.saveAs); err != nil {
+			return err
+		}
+	}
+
 	// Check if token already exists
-	configDir, _ := config.GetConfigDir()
-	tokenPath := configDir + "/token.json"
-	if _, err := os.Stat(tokenPath); err == nil {
-		fmt.Printf("Token file already exists: %s\n", tokenPath)
+	if config.TokenExists() {
+		tokenLocation, _ := config.TokenLocation()
+		fmt.Printf("Token already exists: %s\n", tokenLocation)
 		fmt.Print("Do you want to re-authenticate? [y/N]: ")
 		var response string
 		fmt.Scanln(&response)
@@ -78,10 +197,6 @@ func runAuth(ctx context.Context, opts *authOptions) error {
 	case config.BackendMCP:
 		return runMCPAuth(ctx, opts)
 	case config.BackendAPI, "":
-		oauthCfg, err := config.LoadOAuthConfig()
-		if err != nil {
-			return fmt.Errorf("failed to load OAuth config: %w", err)
-		}
 		return runTraditionalAuth(ctx, opts, oauthCfg)
 	default:
 		return fmt.Errorf("unknown backend: %s", cfg.Backend)
@@ -89,8 +204,12 @@ func runAuth(ctx context.Context, opts *authOptions) error {
 }
 
 func runMCPAuth(ctx context.Context, opts *authOptions) error {
+	// The MCP redirect URI is registered with a fixed port
 	port := defaultMCPCallbackPort
 	callbackURL := fmt.Sprintf("http://127.0.0.1:%d/callback", port)
+	if opts.port != defaultCallbackPort {
+		fmt.Fprintf(os.Stderr, "Warning: --port is ignored with MCP backend (using %d)\n", port)
+	}
 
 	fmt.Println("Using MCP OAuth (Dynamic Client Registration)...")
 
@@ -116,11 +235,15 @@ func runMCPAuth(ctx context.Context, opts *authOptions) error {
 	}
 
 	// Start callback server
-	server, err := gotion.NewCallbackServer(port)
-	if err != nil {
-		return fmt.Errorf("failed to start callback server: %w", err)
+	var server *gotion.CallbackServer
+	if !opts.pasteCode() {
+		var err error
+		server, err = gotion.NewCallbackServer(port)
+		if err != nil {
+			return fmt.Errorf("failed to start callback server: %w", err)
+		}
+		defer server.Close()
 	}
-	defer server.Close()
 
 	// Generate state for CSRF protection
 	state, err := generateState()
@@ -134,27 +257,9 @@ func runMCPAuth(ctx context.Context, opts *authOptions) error {
 		return fmt.Errorf("failed to get auth URL: %w", err)
 	}
 
-	fmt.Println("Opening browser for Notion authorization...")
-	fmt.Printf("If the browser doesn't open, visit this URL:\n%s\n\n", authURL)
-
-	// Open browser
-	if err := openBrowser(authURL); err != nil {
-		fmt.Printf("Failed to open browser: %v\n", err)
-	}
-
-	fmt.Println("Waiting for authorization...")
-
-	// Wait for callback with timeout
-	ctx, cancel := context.WithTimeout(ctx, callbackTimeout)
-	defer cancel()
-
-	if err := server.Start(ctx, state); err != nil {
-		return fmt.Errorf("authorization failed: %w", err)
-	}
-
-	code := server.Code()
-	if code == "" {
-		return fmt.Errorf("no authorization code received")
+	code, err := authorize(ctx, opts, server, authURL, state)
+	if err != nil {
+		return err
 	}
 
 	fmt.Println("Authorization received, exchanging code for token...")
@@ -180,6 +285,7 @@ func runMCPAuth(ctx context.Context, opts *authOptions) error {
 	}
 
 	fmt.Println("Authentication successful!")
+	printTokenStoreHint(opts)
 
 	return nil
 }
@@ -192,13 +298,23 @@ func runTraditionalAuth(ctx context.Context, opts *authOptions, cfg *config.Conf
 	port := opts.port
 
 	// Start callback server
-	server, err := gotion.NewCallbackServer(port)
-	if err != nil {
-		return fmt.Errorf("failed to start callback server: %w", err)
+	var server *gotion.CallbackServer
+	if !opts.pasteCode() {
+		var err error
+		server, err = gotion.NewCallbackServer(port)
+		if err != nil {
+			return fmt.Errorf("failed to start callback server: %w", err)
+		}
+		defer server.Close()
+		port = server.Port()
 	}
-	defer server.Close()
 
-	redirectURI := fmt.Sprintf("http://localhost:%d/callback", server.Port())
+	// The callback server listens on both 127.0.0.1 and ::1, so localhost works
+	// whichever address it resolves to
+	redirectURI := fmt.Sprintf("http://localhost:%d/callback", port)
+	if port != defaultCallbackPort {
+		fmt.Fprintf(os.Stderr, "Warning: %s must be registered as a redirect URI of your integration\n", redirectURI)
+	}
 
 	// Generate state for CSRF protection
 	state, err := generateState()
@@ -216,6 +332,123 @@ func runTraditionalAuth(ctx context.Context, opts *authOptions, cfg *config.Conf
 	// Get authorization URL
 	authURL := oauthClient.GetAuthURL(state)
 
+	code, err := authorize(ctx, opts, server, authURL, state)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Authorization received, exchanging code for token...")
+
+	// Exchange code for token
+	token, err := oauthClient.ExchangeCode(ctx, code)
+	if err != nil {
+		return fmt.Errorf("failed to exchange code: %w", err)
+	}
+
+	// Save token
+	tokenData := &config.TokenData{
+		Backend:       config.BackendAPI,
+		AccessToken:   token.AccessToken,
+		TokenType:     token.TokenType,
+		BotID:         token.BotID,
+		WorkspaceID:   token.WorkspaceID,
+		WorkspaceName: token.WorkspaceName,
+		RefreshToken:  token.RefreshToken,
+	}
+	if !token.ExpiresAt.IsZero() {
+		tokenData.ExpiresAt = token.ExpiresAt.Unix()
+	}
+
+	if err := config.SaveToken(tokenData); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+
+	fmt.Println("Authentication successful!")
+	printTokenStoreHint(opts)
+
+	return nil
+}
+
+func runAuthSetToken(ctx context.Context, opts *authSetTokenOptions) error {
+	data, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("failed to read token: %w", err)
+	}
+
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return fmt.Errorf("empty token")
+	}
+
+	if opts.verify {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %w", err)
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+		defer cancel()
+
+		user, err := api.NewClient(token, cfg.NotionVersion, 0).Me(ctx)
+		if err != nil {
+			return fmt.Errorf("token verification failed: %w", explainNotionError(err, "user"))
+		}
+		fmt.Printf("Token verified for %s.\n", user.Name)
+	}
+
+	tokenData := &config.TokenData{
+		Backend:     config.BackendAPI,
+		AccessToken: token,
+		TokenType:   "bearer",
+	}
+
+	if err := config.SaveToken(tokenData); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+
+	tokenLocation, _ := config.TokenLocation()
+	fmt.Printf("Token saved: %s\n", tokenLocation)
+	return nil
+}
+
+func runAuthRefresh(ctx context.Context) error {
+	tokenData, err := config.LoadToken()
+	if err != nil {
+		return fmt.Errorf("failed to load token (authenticate with 'gotion auth'): %w", err)
+	}
+
+	if tokenData.RefreshToken == "" {
+		fmt.Println("Token does not support refresh (no refresh token). Re-authenticate with 'gotion auth' if it stops working.")
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	refreshed, err := refreshToken(ctx, tokenData)
+	if err != nil {
+		return fmt.Errorf("token refresh failed (re-authenticate with 'gotion auth'): %w", err)
+	}
+
+	if err := config.SaveToken(refreshed); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+
+	if refreshed.ExpiresAt == 0 {
+		fmt.Println("Token refreshed. It does not expire.")
+	} else {
+		fmt.Printf("Token refreshed. Expires at %s.\n", time.Unix(refreshed.ExpiresAt, 0).Format(time.RFC3339))
+	}
+	return nil
+}
+
+// authorize sends the user to authURL and returns the authorization code,
+// received by the callback server or pasted by the user when server is nil
+func authorize(ctx context.Context, opts *authOptions, server *gotion.CallbackServer, authURL, state string) (string, error) {
+	if server == nil {
+		return promptAuthorizationCode(opts, authURL, state)
+	}
+
 	fmt.Println("Opening browser for Notion authorization...")
 	fmt.Printf("If the browser doesn't open, visit this URL:\n%s\n\n", authURL)
 
@@ -231,38 +464,55 @@ func runTraditionalAuth(ctx context.Context, opts *authOptions, cfg *config.Conf
 	defer cancel()
 
 	if err := server.Start(ctx, state); err != nil {
-		return fmt.Errorf("authorization failed: %w", err)
+		return "", fmt.Errorf("authorization failed: %w", err)
+	}
+
+	// Start already rejects a mismatched state; check again before using the code
+	if server.State() != state {
+		return "", fmt.Errorf("authorization failed: state mismatch")
 	}
 
 	code := server.Code()
 	if code == "" {
-		return fmt.Errorf("no authorization code received")
+		return "", fmt.Errorf("no authorization code received")
 	}
+	return code, nil
+}
 
-	fmt.Println("Authorization received, exchanging code for token...")
-
-	// Exchange code for token
-	token, err := oauthClient.ExchangeCode(ctx, code)
-	if err != nil {
-		return fmt.Errorf("failed to exchange code: %w", err)
+// promptAuthorizationCode asks the user to paste the redirect URL, or the code itself with --manual
+func promptAuthorizationCode(opts *authOptions, authURL, state string) (string, error) {
+	fmt.Printf("Visit this URL in a browser to authorize:\n%s\n\n", authURL)
+	fmt.Println("After authorizing, the browser is redirected to a localhost URL that may fail to load.")
+	if opts.manual {
+		fmt.Print("Paste the value of the code parameter from that URL: ")
+	} else {
+		fmt.Print("Paste the full URL from the address bar: ")
 	}
 
-	// Save token
-	tokenData := &config.TokenData{
-		AccessToken:   token.AccessToken,
-		TokenType:     token.TokenType,
-		BotID:         token.BotID,
-		WorkspaceID:   token.WorkspaceID,
-		WorkspaceName: token.WorkspaceName,
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read input: %w", err)
 	}
 
-	if err := config.SaveToken(tokenData); err != nil {
-		return fmt.Errorf("failed to save token: %w", err)
+	input := strings.TrimSpace(line)
+	if input == "" {
+		return "", fmt.Errorf("no authorization code received")
 	}
 
-	fmt.Println("Authentication successful!")
+	if opts.manual {
+		return input, nil
+	}
+	return gotion.ParseCallbackURL(input, state)
+}
 
-	return nil
+// printTokenStoreHint reminds the user to select the keychain store for later commands
+func printTokenStoreHint(opts *authOptions) {
+	if opts.saveAs != "" {
+		fmt.Printf("Token saved as %q. Select it with --profile %s.\n", opts.saveAs, opts.saveAs)
+	}
+	if opts.keychain && os.Getenv(config.TokenStoreEnv) != config.TokenStoreKeychain {
+		fmt.Printf("Token saved to keychain. Set %s=%s so other commands read it.\n", config.TokenStoreEnv, config.TokenStoreKeychain)
+	}
 }
 
 func generateState() (string, error) {
@@ -293,3 +543,17 @@ func openBrowser(url string) error {
 
 	return exec.Command(cmd, args...).Start()
 }
+
+func runAuthMigrate() error {
+	version, err := config.MigrateToken()
+	if err != nil {
+		return fmt.Errorf("failed to migrate token (authenticate with 'gotion auth'): %w", err)
+	}
+
+	if version == config.TokenSchemaVersion {
+		fmt.Printf("Token is already at schema version %d.\n", version)
+		return nil
+	}
+	fmt.Printf("Token upgraded from schema version %d to %d.\n", version, config.TokenSchemaVersion)
+	return nil
+}
@@ -0,0 +1,143 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/longkey1/gotion/internal/gotion"
+	"github.com/longkey1/gotion/internal/gotion/config"
+	"github.com/longkey1/gotion/internal/notion"
+	"github.com/spf13/cobra"
+)
+
+type blocksAppendOptions struct {
+	markdownFile string
+	dryRun       bool
+}
+
+var blocksAppendOpts = &blocksAppendOptions{}
+
+var blocksCmd = &cobra.Command{
+	Use:   "blocks",
+	Short: "Manage page content blocks",
+	Long: `Manage the content blocks of a Notion page.
+
+Requires API backend.`,
+}
+
+var blocksAppendCmd = &cobra.Command{
+	Use:   "append <page_id>",
+	Short: "Append Markdown content to a page",
+	Long: `Append Markdown content to the end of a page.
+
+Supported Markdown: paragraphs, headings (#, ##, ###), bulleted lists (- or *),
+numbered lists (1.) and fenced code blocks. Inline formatting is kept as plain
+text. Other constructs such as quotes, tables, images, horizontal rules and
+nested lists are rejected.
+
+Reads from stdin if --markdown-file is not given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBlocksAppend(cmd.Context(), args[0], blocksAppendOpts)
+	},
+}
+
+var blocksDeleteCmd = &cobra.Command{
+	Use:   "delete <block_id>",
+	Short: "Delete a block",
+	Long: `Delete a block from a page. Notion archives the block, so it can be
+restored from the page history.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runBlocksDelete(cmd.Context(), args[0])
+	},
+}
+
+func init() {
+	blocksAppendCmd.Flags().StringVar(&blocksAppendOpts.markdownFile, "markdown-file", "", "Markdown file to append")
+	blocksAppendCmd.Flags().BoolVar(&blocksAppendOpts.dryRun, "dry-run", false, "Print the requests that would be sent without sending them")
+
+	blocksCmd.AddCommand(blocksAppendCmd)
+	blocksCmd.AddCommand(blocksDeleteCmd)
+	rootCmd.AddCommand(blocksCmd)
+}
+
+func runBlocksAppend(ctx context.Context, pageIDOrURL string, opts *blocksAppendOptions) error {
+	var r io.Reader = os.Stdin
+	if opts.markdownFile != "" {
+		f, err := os.Open(opts.markdownFile)
+		if err != nil {
+			return fmt.Errorf("failed to open file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("failed to read input: %w", err)
+	}
+
+	blocks, err := gotion.ParseMarkdownBlocks(string(data))
+	if err != nil {
+		return fmt.Errorf("failed to parse markdown: %w", err)
+	}
+	if len(blocks) == 0 {
+		return fmt.Errorf("no content to append")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	client, err := notion.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	if opts.dryRun {
+		ctx = notion.WithDryRun(ctx, os.Stdout)
+	}
+
+	if err := client.AppendBlocks(ctx, gotion.ExtractPageID(pageIDOrURL), blocks); err != nil {
+		return err
+	}
+
+	if opts.dryRun {
+		return nil
+	}
+
+	fmt.Printf("Appended %d block(s).\n", len(blocks))
+	return nil
+}
+
+func runBlocksDelete(ctx context.Context, blockIDOrURL string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	client, err := notion.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	block, err := client.DeleteBlock(ctx, gotion.ExtractPageID(blockIDOrURL))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Deleted %s block %s (archived: %t)\n", block.Type, block.ID, block.Archived)
+	return nil
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/longkey1/gotion/internal/gotion/config"
+	"github.com/longkey1/gotion/internal/notion/cache"
+	"github.com/spf13/cobra"
+)
+
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the local page cache",
+	Long: `Manage the on-disk cache of page blocks used by get.
+
+Blocks are reused while the page's last_edited_time is unchanged.`,
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached pages",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCacheClear()
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
+
+// newBlockStore returns the on-disk block cache of the current profile
+func newBlockStore() (*cache.BlockStore, error) {
+	dir, err := config.BlockCacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cache directory: %w", err)
+	}
+	return cache.NewBlockStore(dir), nil
+}
+
+func runCacheClear() error {
+	store, err := newBlockStore()
+	if err != nil {
+		return err
+	}
+
+	if err := store.Clear(); err != nil {
+		return err
+	}
+
+	fmt.Println("Cache cleared")
+	return nil
+}
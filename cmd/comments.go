@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/longkey1/gotion/internal/gotion"
+	"github.com/longkey1/gotion/internal/gotion/config"
+	"github.com/longkey1/gotion/internal/notion"
+	"github.com/spf13/cobra"
+)
+
+type commentsAddOptions struct {
+	text   string
+	dryRun bool
+}
+
+var commentsAddOpts = &commentsAddOptions{}
+
+var commentsCmd = &cobra.Command{
+	Use:   "comments",
+	Short: "List and add page comments",
+	Long: `List and add comments on a Notion page.
+
+Requires API backend.`,
+}
+
+var commentsListCmd = &cobra.Command{
+	Use:   "list <page_id>",
+	Short: "List comments on a page",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCommentsList(cmd.Context(), args[0])
+	},
+}
+
+var commentsAddCmd = &cobra.Command{
+	Use:   "add <page_id>",
+	Short: "Add a comment to a page",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runCommentsAdd(cmd.Context(), args[0], commentsAddOpts)
+	},
+}
+
+func init() {
+	commentsAddCmd.Flags().StringVar(&commentsAddOpts.text, "text", "", "Comment text")
+	_ = commentsAddCmd.MarkFlagRequired("text")
+	commentsAddCmd.Flags().BoolVar(&commentsAddOpts.dryRun, "dry-run", false, "Print the request that would be sent without sending it")
+
+	commentsCmd.AddCommand(commentsListCmd)
+	commentsCmd.AddCommand(commentsAddCmd)
+	rootCmd.AddCommand(commentsCmd)
+}
+
+func newCommentsClient() (notion.Client, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := notion.NewClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return client, nil
+}
+
+func runCommentsList(ctx context.Context, pageIDOrURL string) error {
+	client, err := newCommentsClient()
+	if err != nil {
+		return err
+	}
+
+	comments, err := client.ListComments(ctx, gotion.ExtractPageID(pageIDOrURL))
+	if err != nil {
+		return err
+	}
+
+	for _, comment := range comments {
+		fmt.Printf("[%s] %s\n%s\n\n", comment.CreatedTime, comment.AuthorID, comment.Text)
+	}
+
+	return nil
+}
+
+func runCommentsAdd(ctx context.Context, pageIDOrURL string, opts *commentsAddOptions) error {
+	client, err := newCommentsClient()
+	if err != nil {
+		return err
+	}
+
+	if opts.dryRun {
+		ctx = notion.WithDryRun(ctx, os.Stdout)
+	}
+
+	comment, err := client.CreateComment(ctx, gotion.ExtractPageID(pageIDOrURL), opts.text)
+	if err != nil {
+		return err
+	}
+
+	if opts.dryRun {
+		return nil
+	}
+
+	fmt.Printf("Comment added: %s\n", comment.ID)
+	return nil
+}
@@ -3,24 +3,92 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/longkey1/gotion/internal/gotion/config"
+	"github.com/longkey1/gotion/internal/notion/api"
 	"github.com/spf13/cobra"
 )
 
+type configOptions struct {
+	env         bool
+	showSecrets bool
+}
+
+var configOpts = &configOptions{}
+
 var configCmd = &cobra.Command{
 	Use:   "config",
 	Short: "Show current configuration",
 	Long: `Show current configuration settings.
 
 Displays the effective configuration from environment variables,
-config file, and token file.`,
+config file, and token file.
+
+With --env, prints the resolved settings as export statements that can
+be sourced by a shell or copied into a CI secret store. Secrets are
+masked unless --show-secrets is given.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if configOpts.env {
+			return runConfigEnv(configOpts)
+		}
+		return runConfig()
+	},
+}
+
+type configShowOptions struct {
+	effective bool
+}
+
+var configShowOpts = &configShowOptions{}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Show current configuration",
+	Long: `Show current configuration settings.
+
+With --effective, shows each setting's effective value together with
+the source it was taken from (environment variable, config file,
+token file, or default).`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		if configShowOpts.effective {
+			return runConfigEffective()
+		}
 		return runConfig()
 	},
 }
 
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a value in config.toml",
+	Long: `Set a value in config.toml.
+
+Supported keys: backend, api_client_id (client_id), api_client_secret
+(client_secret), api_token (token), notion_version.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigSet(args[0], args[1])
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the effective value of a setting",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runConfigGet(args[0])
+	},
+}
+
 func init() {
+	configCmd.Flags().BoolVar(&configOpts.env, "env", false, "Print settings as sourceable export statements")
+	configCmd.Flags().BoolVar(&configOpts.showSecrets, "show-secrets", false, "Print secrets unmasked with --env")
+	configShowCmd.Flags().BoolVar(&configShowOpts.effective, "effective", false, "Show effective value and source of each setting")
+
+	configCmd.AddCommand(configShowCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configGetCmd)
 	rootCmd.AddCommand(configCmd)
 }
 
@@ -36,6 +104,13 @@ func runConfig() error {
 	fmt.Println("=====================")
 	fmt.Println()
 
+	// Profile
+	profile := config.Profile()
+	if profile == "" {
+		profile = "(default)"
+	}
+	fmt.Printf("Profile:       %s\n", profile)
+
 	// Backend
 	backend := string(cfg.Backend)
 	if backend == "" {
@@ -71,6 +146,9 @@ func runConfig() error {
 	fmt.Println("-------")
 
 	// Check environment variables
+	if os.Getenv("GOTION_PROFILE") != "" {
+		fmt.Println("GOTION_PROFILE:           set")
+	}
 	if os.Getenv("GOTION_BACKEND") != "" {
 		fmt.Println("GOTION_BACKEND:           set")
 	}
@@ -86,6 +164,9 @@ func runConfig() error {
 	if os.Getenv("GOTION_API_CLIENT_SECRET") != "" {
 		fmt.Println("GOTION_API_CLIENT_SECRET: set")
 	}
+	if os.Getenv("GOTION_NOTION_VERSION") != "" {
+		fmt.Println("GOTION_NOTION_VERSION:    set")
+	}
 
 	// Check config file
 	configPath := configDir + "/config.toml"
@@ -95,17 +176,125 @@ func runConfig() error {
 		fmt.Println("Config file:           (not found)")
 	}
 
-	// Check token file
-	tokenPath := configDir + "/token.json"
-	if _, err := os.Stat(tokenPath); err == nil {
-		fmt.Printf("Token file:            %s\n", tokenPath)
+	// Check token store
+	fmt.Printf("Token store:           %s\n", config.TokenStore())
+	if config.TokenExists() {
+		tokenLocation, _ := config.TokenLocation()
+		fmt.Printf("Token:                 %s\n", tokenLocation)
 	} else {
-		fmt.Println("Token file:            (not found)")
+		fmt.Println("Token:                 (not found)")
+	}
+
+	return nil
+}
+
+func runConfigSet(key, value string) error {
+	if err := config.SetValue(key, value); err != nil {
+		return err
 	}
 
+	fmt.Printf("Set %s\n", key)
 	return nil
 }
 
+func runConfigGet(key string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	value, err := cfg.Get(key)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(value)
+	return nil
+}
+
+func runConfigEffective() error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	backend := string(cfg.Backend)
+	if backend == "" {
+		backend = string(config.BackendAPI)
+	}
+
+	notionVersion := cfg.NotionVersion
+	if notionVersion == "" {
+		notionVersion = api.DefaultNotionVersion
+	}
+
+	rows := []struct {
+		key   string
+		value string
+	}{
+		{"backend", backend},
+		{"api_token", maskValue(cfg.Token, maskToken)},
+		{"api_client_id", maskValue(cfg.ClientID, maskToken)},
+		{"api_client_secret", maskValue(cfg.ClientSecret, func(string) string { return "(set)" })},
+		{"notion_version", notionVersion},
+	}
+
+	profile := config.Profile()
+	if profile == "" {
+		profile = "(default)"
+	}
+	fmt.Printf("Profile: %s\n\n", profile)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tVALUE\tSOURCE")
+	for _, row := range rows {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", row.key, row.value, cfg.Sources[row.key])
+	}
+	return w.Flush()
+}
+
+// configSecretKeys are the settings masked by config --env without --show-secrets
+var configSecretKeys = map[string]bool{
+	"api_token":         true,
+	"api_client_secret": true,
+}
+
+func runConfigEnv(opts *configOptions) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	for _, b := range config.EnvBindings {
+		value, err := cfg.Get(b.Key)
+		if err != nil {
+			return err
+		}
+		// Unset keys are left out so the defaults apply
+		if value == "" {
+			continue
+		}
+		if configSecretKeys[b.Key] && !opts.showSecrets {
+			value = maskToken(value)
+		}
+		fmt.Printf("export %s=%s\n", b.Env, shellQuote(value))
+	}
+	return nil
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// maskValue returns "(not set)" for empty values, otherwise the masked value
+func maskValue(value string, mask func(string) string) string {
+	if value == "" {
+		return "(not set)"
+	}
+	return mask(value)
+}
+
 func maskToken(token string) string {
 	if len(token) <= 8 {
 		return "****"
@@ -17,6 +17,7 @@ type createOptions struct {
 	parentType string
 	title      string
 	file       string
+	dryRun     bool
 }
 
 var createOpts = &createOptions{}
@@ -42,6 +43,7 @@ func init() {
 	createCmd.Flags().StringVar(&createOpts.parentType, "parent-type", "page_id", "Parent type: page_id, database_id, data_source_id")
 	createCmd.Flags().StringVar(&createOpts.title, "title", "", "Page title (overrides input)")
 	createCmd.Flags().StringVar(&createOpts.file, "file", "", "Input file path (default: stdin)")
+	createCmd.Flags().BoolVar(&createOpts.dryRun, "dry-run", false, "Print the request that would be sent without sending it")
 
 	rootCmd.AddCommand(createCmd)
 }
@@ -102,12 +104,19 @@ func runCreate(ctx context.Context, opts *createOptions) error {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
+	if opts.dryRun {
+		ctx = notion.WithDryRun(ctx, os.Stdout)
+	}
+
 	// Create page
 	result, err := client.CreatePage(ctx, createPageOpts)
 	if err != nil {
 		return fmt.Errorf("failed to create page: %w", err)
 	}
 
+	if opts.dryRun {
+		return nil
+	}
 	fmt.Println(string(result.RawJSON))
 	return nil
 }
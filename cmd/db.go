@@ -0,0 +1,513 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/longkey1/gotion/internal/gotion"
+	"github.com/longkey1/gotion/internal/gotion/config"
+	"github.com/longkey1/gotion/internal/notion"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+type dbQueryOptions struct {
+	filter      string
+	where       []string
+	countBy     string
+	format      string
+	sqliteFile  string
+	sqliteTable string
+	newSince    string
+}
+
+// newSinceCheckpoint is the --new-since value that uses the stored checkpoint
+const newSinceCheckpoint = "checkpoint"
+
+var dbQueryOpts = &dbQueryOptions{}
+
+type dbSchemaOptions struct {
+	format string
+}
+
+var dbSchemaOpts = &dbSchemaOptions{}
+
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Work with Notion databases",
+	Long: `Work with Notion databases.
+
+Requires API backend.`,
+}
+
+var dbQueryCmd = &cobra.Command{
+	Use:   "query <database_id>",
+	Short: "Query all rows of a database",
+	Long: `Query all rows of a Notion database, following pagination.
+
+Outputs the rows as a JSON array. With --count-by, outputs the number of
+rows per distinct value of a select, status or multi_select property
+instead (rows are counted under each value of a multi_select).
+
+With --format sqlite, the rows are written to a table in the SQLite file
+given by --sqlite-file, named after the database unless --sqlite-table is
+set. Rows are keyed by page ID in the _id column and replaced on re-export;
+columns for new properties are added to an existing table.
+
+With --new-since, only rows edited at or after the given time are returned,
+using a server-side filter. "--new-since checkpoint" uses the checkpoint
+stored for the database by the previous run (all rows on the first run),
+then advances it to the latest last_edited_time seen. Notion timestamps are
+minute-granular, so rows edited in the checkpoint's minute are returned again.
+
+--where adds simple conditions without writing filter JSON, compiled using
+the property types from the database schema:
+
+  --where "Status=Done"      select, status, title, rich_text: = and !=
+  --where "Estimate>=3"      number: = != > >= < <=
+
+Several --where conditions, --filter and --new-since are combined with AND.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDBQuery(cmd.Context(), args[0], dbQueryOpts)
+	},
+}
+
+var dbSchemaCmd = &cobra.Command{
+	Use:   "schema <database_id>",
+	Short: "Show the property schema of a database",
+	Long: `Show each property of a Notion database with its type, and the allowed
+options of select, multi_select and status properties.
+
+Useful when writing property values for create and update.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDBSchema(cmd.Context(), args[0], dbSchemaOpts)
+	},
+}
+
+func init() {
+	dbQueryCmd.Flags().StringVar(&dbQueryOpts.filter, "filter", "", "Notion filter object as JSON")
+	dbQueryCmd.Flags().StringArrayVar(&dbQueryOpts.where, "where", nil, `Condition on a property, e.g. "Status=Done" or "Estimate>=3" (repeatable)`)
+	dbQueryCmd.Flags().StringVar(&dbQueryOpts.countBy, "count-by", "", "Count rows per value of a select, status or multi_select property")
+	dbQueryCmd.Flags().StringVarP(&dbQueryOpts.format, "format", "o", "json", "Output format: json, text (with --count-by), sqlite")
+	dbQueryCmd.Flags().StringVar(&dbQueryOpts.sqliteFile, "sqlite-file", "notion.db", "SQLite file to write for --format sqlite")
+	dbQueryCmd.Flags().StringVar(&dbQueryOpts.newSince, "new-since", "", `Only rows edited since an RFC 3339 time, or "checkpoint" for incremental sync`)
+	dbQueryCmd.Flags().StringVar(&dbQueryOpts.sqliteTable, "sqlite-table", "", "Table name for --format sqlite (default: database title)")
+
+	// Accept --output as an alias of --format
+	dbQueryCmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "output" {
+			name = "format"
+		}
+		return pflag.NormalizedName(name)
+	})
+
+	dbSchemaCmd.Flags().StringVarP(&dbSchemaOpts.format, "format", "o", "json", "Output format: json, table")
+
+	dbCmd.AddCommand(dbQueryCmd)
+	dbCmd.AddCommand(dbSchemaCmd)
+	rootCmd.AddCommand(dbCmd)
+}
+
+func runDBQuery(ctx context.Context, databaseIDOrURL string, opts *dbQueryOptions) error {
+	switch opts.format {
+	case "json", "text":
+	case "sqlite":
+		if opts.countBy != "" {
+			return fmt.Errorf("--count-by cannot be used with --format sqlite")
+		}
+	default:
+		return fmt.Errorf("unknown format: %s (supported: json, text, sqlite)", opts.format)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	databaseID := gotion.ExtractPageID(databaseIDOrURL)
+
+	// Create client based on backend
+	client, err := notion.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	queryOpts := &notion.QueryDatabaseOptions{}
+	if opts.filter != "" {
+		if !json.Valid([]byte(opts.filter)) {
+			return fmt.Errorf("invalid --filter: not valid JSON")
+		}
+		queryOpts.Filter = json.RawMessage(opts.filter)
+	}
+
+	if len(opts.where) > 0 {
+		database, err := client.GetDatabase(ctx, databaseID)
+		if err != nil {
+			return fmt.Errorf("failed to get database schema: %w", explainNotionError(err, "database"))
+		}
+		whereFilter, err := buildWhereFilter(database.Properties, opts.where)
+		if err != nil {
+			return err
+		}
+		queryOpts.Filter = andFilters(queryOpts.Filter, whereFilter)
+	}
+
+	since, err := resolveNewSince(databaseID, opts.newSince)
+	if err != nil {
+		return err
+	}
+	if since != "" {
+		queryOpts.Filter = withEditedSinceFilter(queryOpts.Filter, since)
+	}
+
+	result, err := client.QueryDatabase(ctx, databaseID, queryOpts)
+	if err != nil {
+		return err
+	}
+
+	if err := writeDBQueryResult(ctx, client, databaseID, result, opts); err != nil {
+		return err
+	}
+
+	if opts.newSince == newSinceCheckpoint {
+		if latest := latestEdit(result.Rows, since); latest != "" {
+			if err := config.SaveCheckpoint(databaseID, latest); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// writeDBQueryResult writes query rows in the format selected by opts
+func writeDBQueryResult(ctx context.Context, client notion.Client, databaseID string, result *notion.QueryDatabaseResult, opts *dbQueryOptions) error {
+
+	if opts.format == "sqlite" {
+		return writeDBSQLite(ctx, client, databaseID, result.Rows, opts)
+	}
+
+	if opts.countBy == "" {
+		fmt.Println(string(result.RawJSON))
+		return nil
+	}
+
+	buckets, err := countBy(result.Rows, opts.countBy)
+	if err != nil {
+		return err
+	}
+
+	if opts.format == "text" {
+		fmt.Print(gotion.FormatHistogram(buckets))
+		return nil
+	}
+
+	output, err := gotion.FormatHistogramJSON(buckets)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+// resolveNewSince returns the last_edited_time lower bound for --new-since, or "" for none
+func resolveNewSince(databaseID, newSince string) (string, error) {
+	switch newSince {
+	case "":
+		return "", nil
+	case newSinceCheckpoint:
+		return config.LoadCheckpoint(databaseID)
+	}
+
+	if _, err := time.Parse(time.RFC3339, newSince); err != nil {
+		return "", fmt.Errorf("invalid --new-since %q: must be an RFC 3339 time or %q", newSince, newSinceCheckpoint)
+	}
+	return newSince, nil
+}
+
+// withEditedSinceFilter combines filter with a last_edited_time on_or_after condition
+func withEditedSinceFilter(filter json.RawMessage, since string) json.RawMessage {
+	timestampFilter := fmt.Sprintf(`{"timestamp":"last_edited_time","last_edited_time":{"on_or_after":%q}}`, since)
+	return andFilters(filter, json.RawMessage(timestampFilter))
+}
+
+// andFilters combines two filters with "and"; a nil filter is left out
+func andFilters(a, b json.RawMessage) json.RawMessage {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+	return json.RawMessage(fmt.Sprintf(`{"and":[%s,%s]}`, a, b))
+}
+
+// whereOperators are the --where comparison operators, longest first so
+// that ">=" is not read as ">"
+var whereOperators = []string{"!=", ">=", "<=", "=", ">", "<"}
+
+// whereConditions maps --where operators to Notion filter conditions
+var whereConditions = map[string]string{
+	"=":  "equals",
+	"!=": "does_not_equal",
+	">":  "greater_than",
+	">=": "greater_than_or_equal_to",
+	"<":  "less_than",
+	"<=": "less_than_or_equal_to",
+}
+
+// buildWhereFilter compiles --where conditions into a Notion filter object,
+// using the property types of the database schema
+func buildWhereFilter(schema map[string]string, exprs []string) (json.RawMessage, error) {
+	var conditions []map[string]interface{}
+	for _, expr := range exprs {
+		name, op, value, err := parseWhere(expr)
+		if err != nil {
+			return nil, err
+		}
+
+		propType, ok := schema[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid --where %q: no property named %q", expr, name)
+		}
+
+		var operand interface{} = value
+		switch propType {
+		case "select", "status", "title", "rich_text":
+			if op != "=" && op != "!=" {
+				return nil, fmt.Errorf("invalid --where %q: %s properties support only = and !=", expr, propType)
+			}
+		case "number":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid --where %q: %q is not a number", expr, value)
+			}
+			operand = n
+		default:
+			return nil, fmt.Errorf("invalid --where %q: %s properties are not supported, use --filter", expr, propType)
+		}
+
+		conditions = append(conditions, map[string]interface{}{
+			"property": name,
+			propType:   map[string]interface{}{whereConditions[op]: operand},
+		})
+	}
+
+	var filter interface{} = conditions[0]
+	if len(conditions) > 1 {
+		filter = map[string]interface{}{"and": conditions}
+	}
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal filter: %w", err)
+	}
+	return data, nil
+}
+
+// parseWhere splits a --where condition at its first operator
+func parseWhere(expr string) (name, op, value string, err error) {
+	index := -1
+	for _, candidate := range whereOperators {
+		i := strings.Index(expr, candidate)
+		// At the same position, the longer operator listed first wins
+		if i >= 0 && (index < 0 || i < index) {
+			index, op = i, candidate
+		}
+	}
+	if index < 0 {
+		return "", "", "", fmt.Errorf("invalid --where %q: expected name=value, or a comparison such as name>=value", expr)
+	}
+
+	name = strings.TrimSpace(expr[:index])
+	value = strings.TrimSpace(expr[index+len(op):])
+	if name == "" {
+		return "", "", "", fmt.Errorf("invalid --where %q: missing property name", expr)
+	}
+	return name, op, value, nil
+}
+
+// latestEdit returns the latest last_edited_time of rows, or since if no row is later
+func latestEdit(rows []notion.DatabaseRow, since string) string {
+	latest, _ := time.Parse(time.RFC3339, since)
+	result := since
+	for _, row := range rows {
+		edited, err := time.Parse(time.RFC3339, row.LastEditedTime)
+		if err == nil && edited.After(latest) {
+			latest = edited
+			result = row.LastEditedTime
+		}
+	}
+	return result
+}
+
+// writeDBSQLite writes rows to a SQLite table with one column per database property
+func writeDBSQLite(ctx context.Context, client notion.Client, databaseID string, rows []notion.DatabaseRow, opts *dbQueryOptions) error {
+	database, err := client.GetDatabase(ctx, databaseID)
+	if err != nil {
+		return err
+	}
+
+	table := opts.sqliteTable
+	if table == "" {
+		table = database.Title
+	}
+	if table == "" {
+		table = database.ID
+	}
+
+	names := make([]string, 0, len(database.Properties))
+	for name := range database.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	columns := []gotion.SQLiteColumn{
+		{Name: "_id", Type: gotion.SQLiteText},
+		{Name: "_url", Type: gotion.SQLiteText},
+	}
+	for _, name := range names {
+		columns = append(columns, gotion.SQLiteColumn{Name: name, Type: sqliteColumnType(database.Properties[name])})
+	}
+
+	records := make([]map[string]string, len(rows))
+	for i, row := range rows {
+		record := make(map[string]string, len(row.Props)+2)
+		for name, value := range row.Props {
+			record[name] = value
+		}
+		record["_id"] = row.ID
+		record["_url"] = row.URL
+		records[i] = record
+	}
+
+	if err := gotion.WriteSQLiteTable(opts.sqliteFile, table, columns, records); err != nil {
+		return err
+	}
+
+	fmt.Printf("Wrote %d row(s) to table %q in %s\n", len(rows), table, opts.sqliteFile)
+	return nil
+}
+
+// sqliteColumnType maps a Notion property type to a SQLite column type
+func sqliteColumnType(propType string) string {
+	switch propType {
+	case "number":
+		return gotion.SQLiteReal
+	case "checkbox":
+		return gotion.SQLiteInteger
+	default:
+		return gotion.SQLiteText
+	}
+}
+
+// countBy counts rows per option of the given property, sorted by count descending
+func countBy(rows []notion.DatabaseRow, property string) ([]gotion.HistogramBucket, error) {
+	counts := make(map[string]int)
+	found := false
+
+	for _, row := range rows {
+		options, ok := row.Options[property]
+		if !ok {
+			continue
+		}
+		found = true
+
+		if len(options) == 0 {
+			counts["(empty)"]++
+			continue
+		}
+		for _, option := range options {
+			counts[option]++
+		}
+	}
+
+	if !found && len(rows) > 0 {
+		return nil, fmt.Errorf("property %q not found or not a select, status or multi_select property", property)
+	}
+
+	buckets := make([]gotion.HistogramBucket, 0, len(counts))
+	for value, count := range counts {
+		buckets = append(buckets, gotion.HistogramBucket{Value: value, Count: count})
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Count != buckets[j].Count {
+			return buckets[i].Count > buckets[j].Count
+		}
+		return buckets[i].Value < buckets[j].Value
+	})
+
+	return buckets, nil
+}
+
+// schemaProperty is a property in db schema output
+type schemaProperty struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`
+	Options []string `json:"options,omitempty"`
+}
+
+func runDBSchema(ctx context.Context, databaseIDOrURL string, opts *dbSchemaOptions) error {
+	if opts.format != "json" && opts.format != "table" {
+		return fmt.Errorf("unknown format: %s (supported: json, table)", opts.format)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	client, err := notion.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	db, err := client.GetDatabase(ctx, gotion.ExtractPageID(databaseIDOrURL))
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(db.Properties))
+	for name := range db.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	properties := make([]schemaProperty, len(names))
+	for i, name := range names {
+		properties[i] = schemaProperty{
+			Name:    name,
+			Type:    db.Properties[name],
+			Options: db.Options[name],
+		}
+	}
+
+	if opts.format == "table" {
+		rows := make([][]string, len(properties))
+		for i, prop := range properties {
+			rows[i] = []string{prop.Name, prop.Type, strings.Join(prop.Options, ", ")}
+		}
+		fmt.Print(gotion.FormatMarkdownTable([]string{"name", "type", "options"}, rows))
+		return nil
+	}
+
+	output, err := json.MarshalIndent(properties, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal schema: %w", err)
+	}
+	fmt.Println(string(output))
+	return nil
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/longkey1/gotion/internal/gotion"
+	"github.com/longkey1/gotion/internal/gotion/config"
+	"github.com/longkey1/gotion/internal/notion"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// deleteConcurrency is the number of pages archived in parallel
+	deleteConcurrency = 4
+	// deleteSummaryTitles is the number of titles shown in the confirmation summary
+	deleteSummaryTitles = 5
+)
+
+type deleteOptions struct {
+	yes    bool
+	dryRun bool
+}
+
+var deleteOpts = &deleteOptions{}
+
+var deleteCmd = &cobra.Command{
+	Use:   "delete <page_id>... | -",
+	Short: "Archive Notion pages",
+	Long: `Archive (move to trash) one or more Notion pages.
+
+Page IDs or URLs can be passed as arguments, or read from stdin
+(one per line) by passing "-". A summary is shown and a single
+confirmation is requested before any page is archived.
+
+Requires API backend.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runDelete(cmd.Context(), args, deleteOpts)
+	},
+}
+
+func init() {
+	deleteCmd.Flags().BoolVarP(&deleteOpts.yes, "yes", "y", false, "Skip confirmation prompt")
+	deleteCmd.Flags().BoolVar(&deleteOpts.dryRun, "dry-run", false, "Show what would be archived without archiving")
+
+	rootCmd.AddCommand(deleteCmd)
+}
+
+func runDelete(ctx context.Context, args []string, opts *deleteOptions) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	// Collect page IDs from args or stdin
+	fromStdin := len(args) == 1 && args[0] == "-"
+	var inputs []string
+	if fromStdin {
+		inputs, err = readPageIDs(os.Stdin)
+		if err != nil {
+			return fmt.Errorf("failed to read page IDs: %w", err)
+		}
+	} else {
+		inputs = args
+	}
+
+	pageIDs := make([]string, 0, len(inputs))
+	for _, input := range inputs {
+		pageIDs = append(pageIDs, gotion.ExtractPageID(input))
+	}
+
+	if len(pageIDs) == 0 {
+		return fmt.Errorf("no page IDs given")
+	}
+
+	// Create client based on backend
+	client, err := notion.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Show summary
+	fmt.Printf("%d page(s) will be archived:\n", len(pageIDs))
+	for i, pageID := range pageIDs {
+		if i >= deleteSummaryTitles {
+			fmt.Printf("  ... and %d more\n", len(pageIDs)-deleteSummaryTitles)
+			break
+		}
+		title := "(unknown title)"
+		// Only the title is needed, so skip fetching the page content
+		if result, err := client.GetPage(ctx, pageID, &notion.GetPageOptions{SkipChildren: true}); err == nil && result.Title != "" {
+			title = result.Title
+		}
+		fmt.Printf("  - %s (%s)\n", title, pageID)
+	}
+
+	if opts.dryRun {
+		fmt.Println("Dry run: no pages were archived.")
+		return nil
+	}
+
+	if !opts.yes {
+		// Stdin is consumed by the page IDs, so ask on the terminal instead
+		in := io.Reader(os.Stdin)
+		if fromStdin {
+			tty, err := os.Open("/dev/tty")
+			if err != nil {
+				return fmt.Errorf("cannot prompt for confirmation when reading IDs from stdin, use --yes")
+			}
+			defer tty.Close()
+			in = tty
+		}
+
+		fmt.Printf("Archive %d page(s)? [y/N]: ", len(pageIDs))
+		var response string
+		fmt.Fscanln(in, &response)
+		if response != "y" && response != "Y" {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	// Archive pages concurrently
+	errs := make([]error, len(pageIDs))
+	sem := make(chan struct{}, deleteConcurrency)
+	var wg sync.WaitGroup
+	for i, pageID := range pageIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, pageID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = client.ArchivePage(ctx, pageID)
+		}(i, pageID)
+	}
+	wg.Wait()
+
+	// Report results
+	failed := 0
+	for i, pageID := range pageIDs {
+		if errs[i] != nil {
+			failed++
+			fmt.Fprintf(os.Stderr, "Failed:   %s: %v\n", pageID, errs[i])
+			continue
+		}
+		fmt.Printf("Archived: %s\n", pageID)
+	}
+
+	fmt.Printf("\n%d archived, %d failed\n", len(pageIDs)-failed, failed)
+
+	if failed > 0 {
+		return fmt.Errorf("failed to archive %d page(s)", failed)
+	}
+	return nil
+}
+
+// readPageIDs reads page IDs or URLs from r, one per line, skipping blank lines
+func readPageIDs(r io.Reader) ([]string, error) {
+	var ids []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		ids = append(ids, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
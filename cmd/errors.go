@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/longkey1/gotion/internal/notion"
+)
+
+// explainNotionError adds guidance to common Notion errors.
+// subject names what was requested, e.g. "page".
+func explainNotionError(err error, subject string) error {
+	switch {
+	case notion.IsNotFound(err):
+		return fmt.Errorf("%s not found or not shared with the integration: %w", subject, err)
+	case notion.IsUnauthorized(err):
+		return fmt.Errorf("token is invalid or revoked (re-authenticate with 'gotion auth'): %w", err)
+	}
+	return err
+}
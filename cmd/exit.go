@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"errors"
+
+	"github.com/longkey1/gotion/internal/gotion/config"
+	"github.com/longkey1/gotion/internal/notion"
+)
+
+// Exit codes, documented in the README for scripts to branch on
+const (
+	// ExitCodeError is the exit code of errors without a more specific code
+	ExitCodeError = 1
+
+	// ExitCodeAuth is the exit code of authentication and configuration errors
+	ExitCodeAuth = 2
+
+	// ExitCodeNotFound is the exit code when a page, database or block is not found
+	ExitCodeNotFound = 3
+
+	// ExitCodeRateLimited is the exit code when Notion rate limiting persists after retries
+	ExitCodeRateLimited = 4
+
+	// ExitCodeNoMatch is the exit code of get --json-path when the path matches nothing
+	ExitCodeNoMatch = 5
+
+	// ExitCodeNotModified is the exit code of get --since when the page has not changed
+	ExitCodeNotModified = 6
+)
+
+// ExitError is an error that makes gotion exit with a specific code
+type ExitError struct {
+	Code    int
+	Message string
+}
+
+func (e *ExitError) Error() string {
+	return e.Message
+}
+
+// ExitCode returns the exit code for an error returned by Execute
+func ExitCode(err error) int {
+	var exitErr *ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		return exitErr.Code
+	case errors.Is(err, config.ErrInvalidConfig), errors.Is(err, errTokenRefresh), notion.IsUnauthorized(err):
+		return ExitCodeAuth
+	case notion.IsNotFound(err):
+		return ExitCodeNotFound
+	case notion.IsRateLimited(err):
+		return ExitCodeRateLimited
+	default:
+		return ExitCodeError
+	}
+}
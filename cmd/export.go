@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/longkey1/gotion/internal/gotion"
+	"github.com/longkey1/gotion/internal/gotion/config"
+	"github.com/longkey1/gotion/internal/notion"
+	"github.com/longkey1/gotion/internal/notion/httplog"
+	"github.com/spf13/cobra"
+)
+
+type exportOptions struct {
+	dir           string
+	maxDepth      int
+	assets        bool
+	assetsBaseURL string
+}
+
+var exportOpts = &exportOptions{}
+
+var exportCmd = &cobra.Command{
+	Use:   "export <page_id>",
+	Short: "Export a page tree to Markdown files",
+	Long: `Export a page and its child pages to Markdown files.
+
+Each page is written as <title>.md with its properties in the frontmatter.
+Child pages are written to a directory named after their parent page.
+Child databases are not exported.
+
+With --assets, images, files and PDFs in the page content and files
+properties are downloaded into <dir>/assets and linked by relative path.
+--assets-base-url links them under a URL instead, for publishing the
+export with the assets served from elsewhere.
+
+Requires API backend.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runExport(cmd.Context(), args[0], exportOpts)
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringVar(&exportOpts.dir, "dir", ".", "Output directory")
+	exportCmd.Flags().IntVar(&exportOpts.maxDepth, "max-depth", 0, "Maximum depth of child pages to export (0 for no limit)")
+	exportCmd.Flags().BoolVar(&exportOpts.assets, "assets", false, "Download images and files into <dir>/assets and link to the local copies")
+	exportCmd.Flags().StringVar(&exportOpts.assetsBaseURL, "assets-base-url", "", "Link downloaded assets under this URL instead of by relative path, e.g. https://cdn.example.com/notion/ (implies --assets)")
+	rootCmd.AddCommand(exportCmd)
+}
+
+func runExport(ctx context.Context, pageIDOrURL string, opts *exportOptions) error {
+	if opts.maxDepth < 0 {
+		return fmt.Errorf("--max-depth must not be negative")
+	}
+
+	if opts.assetsBaseURL != "" {
+		u, err := url.Parse(opts.assetsBaseURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("invalid --assets-base-url %q: must be an absolute URL", opts.assetsBaseURL)
+		}
+		opts.assets = true
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	if cfg.Backend == config.BackendMCP {
+		return fmt.Errorf("export is not supported with MCP backend")
+	}
+
+	client, err := notion.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	e := &exporter{
+		client:   client,
+		maxDepth: opts.maxDepth,
+		visited:  make(map[string]bool),
+		written:  make(map[string]bool),
+	}
+	if opts.assets {
+		e.assets = newAssetStore(filepath.Join(opts.dir, "assets"), opts.assetsBaseURL)
+	}
+	return e.export(ctx, gotion.ExtractPageID(pageIDOrURL), opts.dir, 0)
+}
+
+// exporter writes a page tree to Markdown files
+type exporter struct {
+	client   notion.Client
+	maxDepth int
+	assets   *assetStore     // Downloads page assets; nil leaves asset links as they are
+	visited  map[string]bool // Page IDs already exported, to guard against cycles
+	written  map[string]bool // File paths already written, to avoid overwriting pages with the same title
+}
+
+// export writes the page to dir and recurses into its child pages
+func (e *exporter) export(ctx context.Context, pageID, dir string, depth int) error {
+	if e.visited[pageID] {
+		return nil
+	}
+	e.visited[pageID] = true
+
+	result, err := e.client.GetPage(ctx, pageID, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get page %s: %w", pageID, err)
+	}
+
+	name := e.fileName(dir, result)
+
+	if e.assets != nil {
+		if err := e.assets.localize(ctx, result, dir); err != nil {
+			return err
+		}
+	}
+
+	output := gotion.FormatPage(&gotion.PageOutput{
+		Title:      result.Title,
+		URL:        result.URL,
+		PublicURL:  result.PublicURL,
+		Properties: result.Props,
+		Content:    result.Content,
+	})
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	path := filepath.Join(dir, name+".md")
+	if err := os.WriteFile(path, []byte(output), 0644); err != nil {
+		return fmt.Errorf("failed to write file: %w", err)
+	}
+	fmt.Println(path)
+
+	if e.maxDepth > 0 && depth >= e.maxDepth {
+		return nil
+	}
+
+	for _, child := range result.ChildPages {
+		if child.Type != "child_page" {
+			continue
+		}
+		if err := e.export(ctx, gotion.ExtractPageID(child.ID), filepath.Join(dir, name), depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fileName returns a file name for the page that is not yet used in dir
+func (e *exporter) fileName(dir string, result *notion.PageResult) string {
+	name := sanitizeFileName(result.Title)
+	if e.written[filepath.Join(dir, name)] {
+		id := strings.ReplaceAll(result.ID, "-", "")
+		name = fmt.Sprintf("%s (%s)", name, id[:min(len(id), 8)])
+	}
+	e.written[filepath.Join(dir, name)] = true
+	return name
+}
+
+// sanitizeFileName replaces characters that are not allowed in file names
+func sanitizeFileName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if r < 0x20 || strings.ContainsRune(`/\:*?"<>|`, r) {
+			return '_'
+		}
+		return r
+	}, title)
+
+	name = strings.Trim(strings.TrimSpace(name), ".")
+	if name == "" {
+		return "Untitled"
+	}
+	return name
+}
+
+// assetStore downloads page assets into one directory, each asset once
+type assetStore struct {
+	dir        string // Directory the assets are written to
+	baseURL    string // Assets are linked under this URL when set, else by relative path
+	httpClient *http.Client
+	files      map[string]string // Asset URL without query → file name in dir
+}
+
+func newAssetStore(dir, baseURL string) *assetStore {
+	return &assetStore{
+		dir:        dir,
+		baseURL:    baseURL,
+		httpClient: &http.Client{Transport: httplog.NewTransport()},
+		files:      make(map[string]string),
+	}
+}
+
+// localize downloads the assets of a page written to pageDir and points its
+// content and files properties at them. An asset that cannot be downloaded
+// keeps its original link.
+func (s *assetStore) localize(ctx context.Context, result *notion.PageResult, pageDir string) error {
+	assets, err := gotion.PageAssets(result.RawJSON)
+	if err != nil {
+		return err
+	}
+
+	propLinks := make(map[string][]string)
+	for _, asset := range assets {
+		link := asset.URL
+		fileName, err := s.download(ctx, asset)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		} else {
+			link = s.link(pageDir, fileName)
+		}
+
+		if asset.Property != "" {
+			propLinks[asset.Property] = append(propLinks[asset.Property], link)
+			continue
+		}
+		result.Content = strings.ReplaceAll(result.Content, "]("+asset.URL+")", "]("+link+")")
+	}
+
+	for name, links := range propLinks {
+		result.Props[name] = strings.Join(links, ", ")
+	}
+	return nil
+}
+
+// download saves an asset into s.dir unless it already was, and returns its file name.
+// Notion-hosted URLs are signed afresh on every read, so assets are keyed by URL without the query.
+func (s *assetStore) download(ctx context.Context, asset gotion.Asset) (string, error) {
+	u, err := url.Parse(asset.URL)
+	if err != nil {
+		return "", fmt.Errorf("invalid asset URL %s: %w", asset.URL, err)
+	}
+	key := u.Scheme + "://" + u.Host + u.Path
+	if fileName, ok := s.files[key]; ok {
+		return fileName, nil
+	}
+
+	// The hash keeps assets with the same name apart
+	base := asset.Name
+	if base == "" {
+		base = path.Base(u.Path)
+	}
+	sum := sha256.Sum256([]byte(key))
+	fileName := fmt.Sprintf("%x-%s", sum[:4], sanitizeFileName(base))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, asset.URL, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to download asset %s: %w", key, err)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download asset %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download asset %s: status %d", key, resp.StatusCode)
+	}
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+	filePath := filepath.Join(s.dir, fileName)
+	f, err := os.Create(filePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to write asset: %w", err)
+	}
+	if _, err := io.Copy(f, resp.Body); err != nil {
+		f.Close()
+		os.Remove(filePath)
+		return "", fmt.Errorf("failed to download asset %s: %w", key, err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to write asset: %w", err)
+	}
+
+	s.files[key] = fileName
+	return fileName, nil
+}
+
+// link returns the link to an asset file from a page written to pageDir
+func (s *assetStore) link(pageDir, fileName string) string {
+	if s.baseURL != "" {
+		return strings.TrimSuffix(s.baseURL, "/") + "/" + url.PathEscape(fileName)
+	}
+
+	rel, err := filepath.Rel(pageDir, filepath.Join(s.dir, fileName))
+	if err != nil {
+		rel = filepath.Join(s.dir, fileName)
+	}
+	return (&url.URL{Path: filepath.ToSlash(rel)}).String()
+}
@@ -0,0 +1,171 @@
+package cmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/longkey1/gotion/internal/notion"
+)
+
+// assetServer serves /cat.png and /spec.pdf and answers 404 otherwise,
+// counting the downloads
+func assetServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var downloads atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cat.png", "/spec.pdf":
+			downloads.Add(1)
+			w.Write([]byte("data of " + r.URL.Path))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &downloads
+}
+
+// assetPage returns a page with an image block, a missing file block and a
+// files property, whose assets are served by server
+func assetPage(server *httptest.Server, title string, children ...notion.ChildPage) *notion.PageResult {
+	image := server.URL + "/cat.png?sig=" + title
+	missing := server.URL + "/gone.txt"
+	spec := server.URL + "/spec.pdf"
+	return &notion.PageResult{
+		ID:      "page-" + title,
+		Title:   title,
+		Content: "![A cat](" + image + ")\n\n[file](" + missing + ")\n",
+		RawJSON: []byte(`{
+			"page": {"properties": {"Files": {"type": "files", "files": [
+				{"name": "spec.pdf", "type": "file", "file": {"url": "` + spec + `"}}
+			]}}},
+			"blocks": [
+				{"type": "image", "image": {"type": "file", "file": {"url": "` + image + `"}}},
+				{"type": "file", "file": {"type": "external", "external": {"url": "` + missing + `"}}}
+			]
+		}`),
+		Props:      map[string]string{"Files": "spec.pdf"},
+		ChildPages: children,
+		Source:     "api",
+	}
+}
+
+func readExported(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestExportAssetsRelative(t *testing.T) {
+	server, downloads := assetServer(t)
+	dir := t.TempDir()
+
+	client := &fakeClient{pages: []*notion.PageResult{
+		assetPage(server, "Root", notion.ChildPage{ID: "child", Type: "child_page", Title: "Child"}),
+		assetPage(server, "Child"),
+	}}
+	e := &exporter{
+		client:  client,
+		assets:  newAssetStore(filepath.Join(dir, "assets"), ""),
+		visited: make(map[string]bool),
+		written: make(map[string]bool),
+	}
+	if err := e.export(context.Background(), "root", dir, 0); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(dir, "assets"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cat, spec string
+	for _, entry := range entries {
+		switch {
+		case strings.HasSuffix(entry.Name(), "-cat.png"):
+			cat = entry.Name()
+		case strings.HasSuffix(entry.Name(), "-spec.pdf"):
+			spec = entry.Name()
+		}
+	}
+	if cat == "" || spec == "" || len(entries) != 2 {
+		t.Fatalf("assets = %v, want cat.png and spec.pdf", entries)
+	}
+	if got := readExported(t, filepath.Join(dir, "assets", cat)); got != "data of /cat.png" {
+		t.Errorf("cat.png = %q", got)
+	}
+	// Both pages reference the same assets, which are downloaded once
+	if got := downloads.Load(); got != 2 {
+		t.Errorf("downloads = %d, want 2", got)
+	}
+
+	root := readExported(t, filepath.Join(dir, "Root.md"))
+	for _, want := range []string{
+		"![A cat](assets/" + cat + ")",
+		`"Files": "assets/` + spec + `"`,
+		"[file](" + server.URL + "/gone.txt)", // Not downloadable, so left as is
+	} {
+		if !strings.Contains(root, want) {
+			t.Errorf("Root.md lacks %q:\n%s", want, root)
+		}
+	}
+
+	child := readExported(t, filepath.Join(dir, "Root", "Child.md"))
+	if want := "![A cat](../assets/" + cat + ")"; !strings.Contains(child, want) {
+		t.Errorf("Child.md lacks %q:\n%s", want, child)
+	}
+}
+
+func TestExportAssetsBaseURL(t *testing.T) {
+	server, _ := assetServer(t)
+	dir := t.TempDir()
+
+	e := &exporter{
+		client:  &fakeClient{pages: []*notion.PageResult{assetPage(server, "Root")}},
+		assets:  newAssetStore(filepath.Join(dir, "assets"), "https://cdn.example.com/notion/"),
+		visited: make(map[string]bool),
+		written: make(map[string]bool),
+	}
+	if err := e.export(context.Background(), "root", dir, 0); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	root := readExported(t, filepath.Join(dir, "Root.md"))
+	if !strings.Contains(root, "![A cat](https://cdn.example.com/notion/") || !strings.Contains(root, "-cat.png)") {
+		t.Errorf("image is not linked under the base URL:\n%s", root)
+	}
+	if !strings.Contains(root, `"Files": "https://cdn.example.com/notion/`) {
+		t.Errorf("files property is not linked under the base URL:\n%s", root)
+	}
+}
+
+func TestExportWithoutAssetsKeepsLinks(t *testing.T) {
+	server, downloads := assetServer(t)
+	dir := t.TempDir()
+
+	page := assetPage(server, "Root")
+	e := &exporter{
+		client:  &fakeClient{pages: []*notion.PageResult{page}},
+		visited: make(map[string]bool),
+		written: make(map[string]bool),
+	}
+	if err := e.export(context.Background(), "root", dir, 0); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	if downloads.Load() != 0 {
+		t.Errorf("downloaded %d assets without --assets", downloads.Load())
+	}
+	if root := readExported(t, filepath.Join(dir, "Root.md")); !strings.Contains(root, "![A cat]("+server.URL+"/cat.png?sig=Root)") {
+		t.Errorf("image link was changed without --assets:\n%s", root)
+	}
+}
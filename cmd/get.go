@@ -1,41 +1,116 @@
 package cmd
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"strings"
+	"sync"
+	"text/template"
+	"time"
 
 	"github.com/longkey1/gotion/internal/gotion"
 	"github.com/longkey1/gotion/internal/gotion/config"
 	"github.com/longkey1/gotion/internal/notion"
+	"github.com/longkey1/gotion/internal/notion/api"
 	"github.com/spf13/cobra"
 )
 
 type getOptions struct {
-	filterProperties string
-	format           string
+	filterProperties   string
+	format             string
+	waitForConsistency bool
+	expect             string
+	editedAfter        string
+	waitTimeout        time.Duration
+	followChildPages   bool
+	since              string
+	propertyTypes      string
+	raw                bool
+	stripIDs           bool
+	stripFields        string
+	resolveUsers       bool
+	redact             []string
+	redactEmails       bool
+	redactURLs         bool
+	compact            bool
+	template           string
+	noBlocks           bool
+	jsonPath           string
+	cache              bool
+	propertiesOnly     bool
+	delimiter          string
+	failFast           bool
+	blockConcurrency   int
+
+	outputTemplate *template.Template // Parsed --template
 }
 
 var getOpts = &getOptions{}
 
+// getConcurrency is the number of pages fetched in parallel when several IDs are given
+const getConcurrency = 4
+
 var getCmd = &cobra.Command{
-	Use:   "get <page_id>",
-	Short: "Get a single Notion page",
-	Long:  `Retrieve a Notion page by its ID or URL and display its properties.`,
-	Args:  cobra.ExactArgs(1),
+	Use:   "get <page_id>...",
+	Short: "Get Notion pages",
+	Long: `Retrieve Notion pages by ID or URL and display their properties.
+
+Several pages are fetched concurrently and printed in the order given,
+separated by --delimiter. A page that fails is reported on stderr
+without stopping the others, unless --fail-fast is set.
+
+Each page's nested blocks are fetched with up to --block-concurrency
+requests at once, so several IDs can have 4 times that many requests in
+flight. --rate-limit caps the request rate regardless.`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runGet(cmd.Context(), args[0], getOpts)
+		return runGet(cmd.Context(), args, getOpts)
 	},
 }
 
 func init() {
-	getCmd.Flags().StringVar(&getOpts.filterProperties, "filter-properties", "", "Filter properties to retrieve (comma-separated)")
-	getCmd.Flags().StringVar(&getOpts.format, "format", "json", "Output format: json, markdown")
+	getCmd.Flags().StringVar(&getOpts.filterProperties, "filter-properties", "", "Properties to retrieve by name or ID (comma-separated); names are resolved to IDs with an extra request (API backend)")
+	getCmd.Flags().StringVar(&getOpts.format, "format", "json", "Output format: json, markdown, template")
+	getCmd.Flags().StringVar(&getOpts.template, "template", "", "Go template for --format template, e.g. '{{.Title}} {{.URL}}' (fields: ID, Title, URL, Props, LastEdited)")
+	getCmd.Flags().BoolVar(&getOpts.waitForConsistency, "wait-for-consistency", false, "Retry the read until --expect or --edited-after is satisfied (best-effort)")
+	getCmd.Flags().StringVar(&getOpts.expect, "expect", "", "Condition for --wait-for-consistency: property value (name=value)")
+	getCmd.Flags().StringVar(&getOpts.editedAfter, "edited-after", "", "Condition for --wait-for-consistency: last_edited_time after this RFC 3339 time")
+	getCmd.Flags().BoolVar(&getOpts.followChildPages, "follow-child-pages", false, "List the page's child pages and databases instead of the page")
+	getCmd.Flags().StringVar(&getOpts.since, "since", "", "Output only if edited after this time (RFC 3339 or duration like 24h); otherwise exit with code 6")
+	getCmd.Flags().StringVar(&getOpts.propertyTypes, "property-type-filter", "", "Show only properties of these types, e.g. date,relation (comma-separated, API backend)")
+	getCmd.Flags().BoolVar(&getOpts.noBlocks, "no-blocks", false, "Fetch properties only, skipping page content; JSON output is the page object alone (API backend)")
+	getCmd.Flags().BoolVar(&getOpts.cache, "cache", false, "Reuse page content cached unencrypted under the config directory while the page is unchanged (API backend)")
+	getCmd.Flags().BoolVar(&getOpts.propertiesOnly, "properties-only", false, "Print only the property list as 'name: value' lines, without title, URL or content (API backend)")
+	getCmd.Flags().BoolVar(&getOpts.raw, "raw", false, "Print the combined page and blocks JSON verbatim, ignoring --format (API backend)")
+	getCmd.Flags().BoolVar(&getOpts.stripIDs, "strip-ids", false, "Remove ID fields from JSON output for cleaner diffs")
+	getCmd.Flags().StringVar(&getOpts.stripFields, "strip-fields", strings.Join(gotion.DefaultStripFields, ","), "Fields removed by --strip-ids (comma-separated)")
+	getCmd.Flags().BoolVar(&getOpts.resolveUsers, "resolve-users", false, "Look up names of people property users that have only an ID (extra API calls, API backend)")
+	getCmd.Flags().StringArrayVar(&getOpts.redact, "redact", nil, "Mask the value of this property in the output (repeatable, API backend)")
+	getCmd.Flags().BoolVar(&getOpts.redactEmails, "redact-all-emails", false, "Mask all email addresses in the output")
+	getCmd.Flags().BoolVar(&getOpts.redactURLs, "redact-all-urls", false, "Mask all URLs in the output")
+	getCmd.Flags().StringVar(&getOpts.jsonPath, "json-path", "", "Print the values matching this JSONPath in the raw JSON, e.g. '$.page.properties.Name' (exit code 5 if nothing matches)")
+	getCmd.Flags().StringVar(&getOpts.delimiter, "delimiter", "", "Line printed between pages when several IDs are given (default: an empty line)")
+	getCmd.Flags().BoolVar(&getOpts.failFast, "fail-fast", false, "Stop at the first page that fails when several IDs are given")
+	getCmd.Flags().IntVar(&getOpts.blockConcurrency, "block-concurrency", api.DefaultBlockConcurrency, "Concurrent requests for nested blocks of each page (API backend)")
+	getCmd.Flags().BoolVar(&getOpts.compact, "compact", false, "Print JSON output on a single line instead of indented")
+	getCmd.Flags().DurationVar(&getOpts.waitTimeout, "wait-timeout", 30*time.Second, "Maximum time to wait for consistency")
 
 	rootCmd.AddCommand(getCmd)
 }
 
-func runGet(ctx context.Context, pageIDOrURL string, opts *getOptions) error {
+func runGet(ctx context.Context, pageIDsOrURLs []string, opts *getOptions) error {
+	if opts.format == "template" {
+		tmpl, err := gotion.ParseOutputTemplate(opts.template)
+		if err != nil {
+			return err
+		}
+		opts.outputTemplate = tmpl
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -45,51 +120,500 @@ func runGet(ctx context.Context, pageIDOrURL string, opts *getOptions) error {
 		return err
 	}
 
-	// Extract page ID from URL if needed
-	pageID := gotion.ExtractPageID(pageIDOrURL)
-
 	// Create client based on backend
 	client, err := notion.NewClient(cfg)
 	if err != nil {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
+	if opts.propertiesOnly {
+		if opts.raw || opts.jsonPath != "" || opts.followChildPages {
+			return fmt.Errorf("--properties-only cannot be used with --raw, --json-path or --follow-child-pages")
+		}
+		if cfg.Backend == config.BackendMCP {
+			return fmt.Errorf("--properties-only is not supported with MCP backend")
+		}
+		// Content is not printed, so skip fetching it
+		opts.noBlocks = true
+	}
+
+	if opts.noBlocks && opts.followChildPages {
+		return fmt.Errorf("--no-blocks cannot be used with --follow-child-pages")
+	}
+
+	if opts.blockConcurrency < 1 {
+		return fmt.Errorf("--block-concurrency must be positive")
+	}
+
 	// Build options
 	var getPageOpts *notion.GetPageOptions
-	if opts.filterProperties != "" {
-		filterProps := strings.Split(opts.filterProperties, ",")
-		for i := range filterProps {
-			filterProps[i] = strings.TrimSpace(filterProps[i])
-		}
+	if opts.filterProperties != "" || opts.propertyTypes != "" || opts.resolveUsers || opts.noBlocks {
 		getPageOpts = &notion.GetPageOptions{
-			FilterProperties: filterProps,
+			FilterProperties: splitList(opts.filterProperties),
+			SkipChildren:     opts.noBlocks,
+			PropertyTypes:    splitList(opts.propertyTypes),
+			ResolveUsers:     opts.resolveUsers,
+		}
+	}
+
+	// Options bypass the in-memory page cache, so only set a non-default concurrency
+	if opts.blockConcurrency != api.DefaultBlockConcurrency {
+		if getPageOpts == nil {
+			getPageOpts = &notion.GetPageOptions{}
+		}
+		getPageOpts.BlockConcurrency = opts.blockConcurrency
+	}
+
+	// Reuse cached blocks while the page is unchanged. Waiting for a write
+	// to become visible must see fresh blocks, so it bypasses the cache.
+	if opts.cache && !opts.noBlocks && !opts.waitForConsistency {
+		store, err := newBlockStore()
+		if err != nil {
+			return err
+		}
+		if getPageOpts == nil {
+			getPageOpts = &notion.GetPageOptions{}
+		}
+		getPageOpts.BlockCache = store
+	}
+
+	if len(pageIDsOrURLs) > 1 {
+		return getPages(ctx, client, pageIDsOrURLs, getPageOpts, opts)
+	}
+
+	output, err := getPageOutput(ctx, client, pageIDsOrURLs[0], getPageOpts, opts)
+	if err != nil {
+		return err
+	}
+	fmt.Print(output)
+	return nil
+}
+
+// getPages fetches several pages concurrently and prints them in input order
+func getPages(ctx context.Context, client notion.Client, pageIDsOrURLs []string, getPageOpts *notion.GetPageOptions, opts *getOptions) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	outputs := make([]string, len(pageIDsOrURLs))
+	errs := make([]error, len(pageIDsOrURLs))
+	sem := make(chan struct{}, getConcurrency)
+	var wg sync.WaitGroup
+	for i, pageID := range pageIDsOrURLs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, pageID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			outputs[i], errs[i] = getPageOutput(ctx, client, pageID, getPageOpts, opts)
+			if errs[i] != nil && opts.failFast && !isNotModified(errs[i]) {
+				cancel()
+			}
+		}(i, pageID)
+	}
+	wg.Wait()
+
+	// With --fail-fast, report the failure that cancelled the others
+	if opts.failFast {
+		for i, err := range errs {
+			if err != nil && !isNotModified(err) && !errors.Is(err, context.Canceled) {
+				return fmt.Errorf("%s: %w", pageIDsOrURLs[i], err)
+			}
+		}
+	}
+
+	var printed, failed, notModified int
+	for i, pageID := range pageIDsOrURLs {
+		if err := errs[i]; err != nil {
+			// Pages unchanged since --since are left out of the output
+			if isNotModified(err) {
+				notModified++
+				continue
+			}
+			failed++
+			fmt.Fprintf(os.Stderr, "Failed: %s: %v\n", pageID, err)
+			continue
+		}
+
+		if printed > 0 {
+			fmt.Println(opts.delimiter)
+		}
+		fmt.Print(outputs[i])
+		if !strings.HasSuffix(outputs[i], "\n") {
+			fmt.Println()
+		}
+		printed++
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("failed to get %d of %d pages", failed, len(pageIDsOrURLs))
+	}
+	if notModified == len(pageIDsOrURLs) {
+		return &ExitError{
+			Code:    ExitCodeNotModified,
+			Message: fmt.Sprintf("not modified since %s", opts.since),
+		}
+	}
+	return nil
+}
+
+// isNotModified reports whether err is the get --since result for an unchanged page
+func isNotModified(err error) bool {
+	var exitErr *ExitError
+	return errors.As(err, &exitErr) && exitErr.Code == ExitCodeNotModified
+}
+
+// getPageOutput fetches a page and renders it as selected by opts
+func getPageOutput(ctx context.Context, client notion.Client, pageIDOrURL string, getPageOpts *notion.GetPageOptions, opts *getOptions) (string, error) {
+	// Extract page ID from URL if needed
+	pageID := gotion.ExtractPageID(pageIDOrURL)
+
+	// Skip the full fetch if the page is unchanged since --since
+	if opts.since != "" {
+		modified, err := modifiedSince(ctx, client, pageID, opts.since)
+		if err != nil {
+			return "", err
+		}
+		if !modified {
+			return "", &ExitError{
+				Code:    ExitCodeNotModified,
+				Message: fmt.Sprintf("not modified since %s", opts.since),
+			}
 		}
 	}
 
 	// Get page
-	result, err := client.GetPage(ctx, pageID, getPageOpts)
+	var result *notion.PageResult
+	if opts.waitForConsistency {
+		cond, err := buildConsistencyCondition(opts)
+		if err != nil {
+			return "", err
+		}
+		result, err = waitForConsistency(ctx, client, pageID, getPageOpts, cond, opts.waitTimeout)
+		if err != nil {
+			return "", err
+		}
+	} else {
+		var err error
+		result, err = client.GetPage(ctx, pageID, getPageOpts)
+		if err != nil {
+			return "", fmt.Errorf("failed to get page: %w", explainNotionError(err, "page"))
+		}
+	}
+
+	if opts.followChildPages {
+		return formatChildPages(result, opts.format)
+	}
+
+	if opts.propertyTypes != "" && result.Source == "mcp" {
+		return "", fmt.Errorf("--property-type-filter is not supported with MCP backend")
+	}
+
+	if opts.resolveUsers && result.Source == "mcp" {
+		return "", fmt.Errorf("--resolve-users is not supported with MCP backend")
+	}
+
+	if opts.noBlocks && result.Source == "mcp" {
+		return "", fmt.Errorf("--no-blocks is not supported with MCP backend")
+	}
+
+	if len(opts.redact) > 0 {
+		if result.Source == "mcp" {
+			return "", fmt.Errorf("--redact is not supported with MCP backend")
+		}
+		if err := redactProperties(result, opts.redact, !opts.noBlocks); err != nil {
+			return "", err
+		}
+	}
+
+	if opts.jsonPath != "" {
+		output, err := extractJSONPath(result.RawJSON, opts.jsonPath, opts.compact)
+		if err != nil {
+			return "", err
+		}
+		return gotion.RedactPatterns(output, opts.redactEmails, opts.redactURLs), nil
+	}
+
+	output, err := formatGetOutput(client, result, opts)
 	if err != nil {
-		return fmt.Errorf("failed to get page: %w", err)
+		return "", err
+	}
+
+	if opts.compact && (opts.raw || opts.format == "json") {
+		output, err = gotion.CompactJSON(output)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return gotion.RedactPatterns(output, opts.redactEmails, opts.redactURLs), nil
+}
+
+// formatGetOutput renders the page in the format selected by opts
+func formatGetOutput(client notion.Client, result *notion.PageResult, opts *getOptions) (string, error) {
+	if opts.raw {
+		if result.Source == "mcp" {
+			return "", fmt.Errorf("--raw is not supported with MCP backend")
+		}
+		output, err := stripIDs(string(result.RawJSON), opts)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(output, "\n") + "\n", nil
+	}
+
+	if opts.propertiesOnly {
+		return gotion.FormatProperties(result.Props), nil
 	}
 
-	// Format output
 	switch opts.format {
 	case "markdown":
-		output := gotion.FormatPage(&gotion.PageOutput{
-			Title:   result.Title,
-			URL:     result.URL,
-			Content: result.Content,
-		})
-		fmt.Print(output)
+		return gotion.FormatPage(&gotion.PageOutput{
+			Title:     result.Title,
+			URL:       result.URL,
+			PublicURL: result.PublicURL,
+			Content:   result.Content,
+		}), nil
 	case "json":
 		output, err := client.FormatPage(result)
 		if err != nil {
-			return err
+			return "", err
 		}
-		fmt.Print(output)
+		return stripIDs(output, opts)
+	case "template":
+		return gotion.ExecuteOutputTemplate(opts.outputTemplate, &gotion.TemplateData{
+			ID:         result.ID,
+			Title:      result.Title,
+			URL:        result.URL,
+			Props:      result.Props,
+			LastEdited: result.LastEditedTime,
+		})
 	default:
-		return fmt.Errorf("unknown format: %s (supported: json, markdown)", opts.format)
+		return "", fmt.Errorf("unknown format: %s (supported: json, markdown, template)", opts.format)
+	}
+}
+
+// extractJSONPath prints each value matching path in rawJSON on its own:
+// strings as plain text, other values as JSON
+func extractJSONPath(rawJSON []byte, path string, compact bool) (string, error) {
+	matches, err := gotion.EvalJSONPath(rawJSON, path)
+	if err != nil {
+		return "", err
+	}
+	if len(matches) == 0 {
+		return "", &ExitError{
+			Code:    ExitCodeNoMatch,
+			Message: fmt.Sprintf("no match for %s", path),
+		}
+	}
+
+	var sb strings.Builder
+	for _, match := range matches {
+		var s string
+		if err := json.Unmarshal(match, &s); err == nil {
+			sb.WriteString(s + "\n")
+			continue
+		}
+
+		value := string(match)
+		if !compact {
+			var buf bytes.Buffer
+			if err := json.Indent(&buf, match, "", "  "); err != nil {
+				return "", fmt.Errorf("failed to format match: %w", err)
+			}
+			value = buf.String()
+		}
+		sb.WriteString(value + "\n")
+	}
+	return sb.String(), nil
+}
+
+// redactProperties masks the named properties in the page's raw JSON and extracted values.
+// withBlocks reports whether the raw JSON is the combined {page, blocks} object rather than the page alone.
+func redactProperties(result *notion.PageResult, names []string, withBlocks bool) error {
+	var titleRedacted bool
+	if withBlocks {
+		var combined map[string]json.RawMessage
+		if err := json.Unmarshal(result.RawJSON, &combined); err != nil {
+			return fmt.Errorf("failed to unmarshal page: %w", err)
+		}
+
+		page, redacted, err := gotion.RedactProperties(combined["page"], names)
+		if err != nil {
+			return err
+		}
+		combined["page"] = page
+		titleRedacted = redacted
+
+		rawJSON, err := json.MarshalIndent(combined, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal page: %w", err)
+		}
+		result.RawJSON = rawJSON
+	} else {
+		page, redacted, err := gotion.RedactProperties(result.RawJSON, names)
+		if err != nil {
+			return err
+		}
+		result.RawJSON = page
+		titleRedacted = redacted
+	}
+
+	for _, name := range names {
+		if _, ok := result.Props[name]; ok {
+			result.Props[name] = gotion.RedactMask
+		}
+	}
+	if titleRedacted {
+		result.Title = gotion.RedactMask
 	}
 
 	return nil
 }
+
+// stripIDs removes the --strip-fields keys from JSON output when --strip-ids is set
+func stripIDs(output string, opts *getOptions) (string, error) {
+	if !opts.stripIDs {
+		return output, nil
+	}
+
+	stripped, err := gotion.StripJSONFields([]byte(output), splitList(opts.stripFields))
+	if err != nil {
+		return "", fmt.Errorf("failed to strip IDs: %w", err)
+	}
+	return string(stripped), nil
+}
+
+// splitList splits a comma-separated list, trimming spaces and dropping empty items
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// modifiedSince fetches page metadata only and reports whether the page was
+// edited after since, an RFC 3339 time or a duration before now
+func modifiedSince(ctx context.Context, client notion.Client, pageID, since string) (bool, error) {
+	baseline, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		d, durErr := time.ParseDuration(since)
+		if durErr != nil {
+			return false, fmt.Errorf("invalid --since %q: must be an RFC 3339 time or a duration", since)
+		}
+		baseline = time.Now().Add(-d)
+	}
+
+	result, err := client.GetPage(ctx, pageID, &notion.GetPageOptions{SkipChildren: true})
+	if err != nil {
+		return false, fmt.Errorf("failed to get page: %w", explainNotionError(err, "page"))
+	}
+
+	if result.LastEditedTime == "" {
+		return false, fmt.Errorf("--since is not supported with MCP backend")
+	}
+
+	edited, err := time.Parse(time.RFC3339, result.LastEditedTime)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse last_edited_time: %w", err)
+	}
+
+	return edited.After(baseline), nil
+}
+
+// formatChildPages renders the child pages and databases of a page
+func formatChildPages(result *notion.PageResult, format string) (string, error) {
+	if result.Source == "mcp" {
+		return "", fmt.Errorf("--follow-child-pages is not supported with MCP backend")
+	}
+
+	switch format {
+	case "markdown":
+		var sb strings.Builder
+		for _, child := range result.ChildPages {
+			sb.WriteString(fmt.Sprintf("- %s (%s: %s)\n", child.Title, child.Type, child.ID))
+		}
+		return sb.String(), nil
+	case "json":
+		children := result.ChildPages
+		if children == nil {
+			children = []notion.ChildPage{}
+		}
+		output, err := json.MarshalIndent(children, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal child pages: %w", err)
+		}
+		return string(output) + "\n", nil
+	default:
+		return "", fmt.Errorf("unknown format: %s (supported: json, markdown)", format)
+	}
+}
+
+// buildConsistencyCondition builds the predicate used by --wait-for-consistency
+func buildConsistencyCondition(opts *getOptions) (func(*notion.PageResult) bool, error) {
+	if opts.expect == "" && opts.editedAfter == "" {
+		return nil, fmt.Errorf("--wait-for-consistency requires --expect or --edited-after")
+	}
+
+	var name, value string
+	if opts.expect != "" {
+		var ok bool
+		name, value, ok = strings.Cut(opts.expect, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --expect %q: must be name=value", opts.expect)
+		}
+	}
+
+	var baseline time.Time
+	if opts.editedAfter != "" {
+		var err error
+		baseline, err = time.Parse(time.RFC3339, opts.editedAfter)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --edited-after %q: %w", opts.editedAfter, err)
+		}
+	}
+
+	return func(result *notion.PageResult) bool {
+		if name != "" && result.Props[name] != value {
+			return false
+		}
+		if !baseline.IsZero() {
+			edited, err := time.Parse(time.RFC3339, result.LastEditedTime)
+			if err != nil || !edited.After(baseline) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// waitForConsistency re-reads the page with backoff until cond is satisfied or
+// the timeout expires. Notion reads are eventually consistent, so this is best-effort.
+func waitForConsistency(ctx context.Context, client notion.Client, pageID string, opts *notion.GetPageOptions, cond func(*notion.PageResult) bool, timeout time.Duration) (*notion.PageResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	delay := 500 * time.Millisecond
+	for {
+		result, err := client.GetPage(ctx, pageID, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get page: %w", explainNotionError(err, "page"))
+		}
+		if cond(result) {
+			return result, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("page did not become consistent within %s", timeout)
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > 5*time.Second {
+			delay = 5 * time.Second
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/longkey1/gotion/internal/gotion"
+	"github.com/longkey1/gotion/internal/notion"
+	"github.com/longkey1/gotion/internal/notion/api"
+)
+
+// fakeClient serves GetPage from pages, one per call, repeating the last one,
+// or fails every call with err
+type fakeClient struct {
+	notion.Client
+	pages []*notion.PageResult
+	err   error
+	calls int
+}
+
+func (c *fakeClient) GetPage(ctx context.Context, pageID string, opts *notion.GetPageOptions) (*notion.PageResult, error) {
+	if c.err != nil {
+		c.calls++
+		return nil, c.err
+	}
+	page := c.pages[min(c.calls, len(c.pages)-1)]
+	c.calls++
+	return page, nil
+}
+
+func (c *fakeClient) FormatPage(result *notion.PageResult) (string, error) {
+	return string(result.RawJSON), nil
+}
+
+// newSecretPage returns a page whose title and Email property hold secrets
+func newSecretPage() *notion.PageResult {
+	return &notion.PageResult{
+		ID:      "page-id",
+		Title:   "Secret Title",
+		URL:     "https://www.notion.so/page",
+		Content: "Contact bob@example.com or see https://example.com/private\n",
+		RawJSON: []byte(`{
+  "page": {
+    "object": "page",
+    "properties": {
+      "Name": {"id": "title", "type": "title", "title": [{"plain_text": "Secret Title"}]},
+      "Email": {"id": "a", "type": "email", "email": "alice@example.com"},
+      "Status": {"id": "b", "type": "select", "select": {"name": "Done"}}
+    }
+  },
+  "blocks": []
+}`),
+		Props: map[string]string{
+			"Name":   "Secret Title",
+			"Email":  "alice@example.com",
+			"Status": "Done",
+		},
+		Source: "api",
+	}
+}
+
+func TestGetPageOutputRedacts(t *testing.T) {
+	tmpl, err := gotion.ParseOutputTemplate(`{{.Title}} {{index .Props "Email"}} {{index .Props "Status"}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts getOptions
+	}{
+		{name: "json", opts: getOptions{format: "json"}},
+		{name: "raw", opts: getOptions{format: "json", raw: true}},
+		{name: "markdown", opts: getOptions{format: "markdown"}},
+		{name: "template", opts: getOptions{format: "template", outputTemplate: tmpl}},
+		{name: "properties only", opts: getOptions{format: "json", propertiesOnly: true}},
+		{name: "json path", opts: getOptions{format: "json", jsonPath: "$.page.properties.Email.email"}},
+	}
+
+	secrets := []string{"Secret Title", "alice@example.com", "bob@example.com", "https://example.com/private"}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.redact = []string{"Name", "Email"}
+			opts.redactEmails = true
+			opts.redactURLs = true
+
+			client := &fakeClient{pages: []*notion.PageResult{newSecretPage()}}
+			output, err := getPageOutput(context.Background(), client, "page-id", nil, &opts)
+			if err != nil {
+				t.Fatalf("getPageOutput: %v", err)
+			}
+
+			for _, secret := range secrets {
+				if strings.Contains(output, secret) {
+					t.Errorf("output contains %q:\n%s", secret, output)
+				}
+			}
+			if !strings.Contains(output, gotion.RedactMask) {
+				t.Errorf("output has no %q:\n%s", gotion.RedactMask, output)
+			}
+		})
+	}
+}
+
+func TestRedactPropertiesKeepsOthers(t *testing.T) {
+	result := newSecretPage()
+	if err := redactProperties(result, []string{"Email"}, true); err != nil {
+		t.Fatalf("redactProperties: %v", err)
+	}
+
+	if result.Title != "Secret Title" {
+		t.Errorf("Title = %q, want it kept when the title is not redacted", result.Title)
+	}
+	if result.Props["Email"] != gotion.RedactMask {
+		t.Errorf("Props[Email] = %q, want %q", result.Props["Email"], gotion.RedactMask)
+	}
+	if result.Props["Status"] != "Done" {
+		t.Errorf("Props[Status] = %q, want %q", result.Props["Status"], "Done")
+	}
+	if strings.Contains(string(result.RawJSON), "alice@example.com") {
+		t.Errorf("RawJSON still contains the email:\n%s", result.RawJSON)
+	}
+	if !strings.Contains(string(result.RawJSON), `"Done"`) {
+		t.Errorf("RawJSON lost an unredacted property:\n%s", result.RawJSON)
+	}
+}
+
+func TestWaitForConsistency(t *testing.T) {
+	stale := &notion.PageResult{
+		LastEditedTime: "2024-06-01T12:00:00Z",
+		Props:          map[string]string{"Status": "Todo"},
+	}
+	fresh := &notion.PageResult{
+		LastEditedTime: "2024-06-01T12:05:00Z",
+		Props:          map[string]string{"Status": "Done"},
+	}
+
+	tests := []struct {
+		name      string
+		opts      getOptions
+		pages     []*notion.PageResult
+		timeout   time.Duration
+		wantErr   bool
+		wantCalls int
+	}{
+		{
+			name:      "expected property after a stale read",
+			opts:      getOptions{expect: "Status=Done"},
+			pages:     []*notion.PageResult{stale, fresh},
+			timeout:   5 * time.Second,
+			wantCalls: 2,
+		},
+		{
+			name:      "edited after baseline after stale reads",
+			opts:      getOptions{editedAfter: "2024-06-01T12:01:00Z"},
+			pages:     []*notion.PageResult{stale, stale, fresh},
+			timeout:   5 * time.Second,
+			wantCalls: 3,
+		},
+		{
+			name:      "already consistent",
+			opts:      getOptions{expect: "Status=Done"},
+			pages:     []*notion.PageResult{fresh},
+			timeout:   5 * time.Second,
+			wantCalls: 1,
+		},
+		{
+			name:      "never consistent",
+			opts:      getOptions{expect: "Status=Done"},
+			pages:     []*notion.PageResult{stale},
+			timeout:   200 * time.Millisecond,
+			wantErr:   true,
+			wantCalls: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cond, err := buildConsistencyCondition(&tt.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			client := &fakeClient{pages: tt.pages}
+			result, err := waitForConsistency(context.Background(), client, "page-id", nil, cond, tt.timeout)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("waitForConsistency error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && result != fresh {
+				t.Errorf("result = %+v, want the fresh page", result)
+			}
+			if client.calls != tt.wantCalls {
+				t.Errorf("GetPage calls = %d, want %d", client.calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestWaitForConsistencyExplainsNotFound(t *testing.T) {
+	cond, err := buildConsistencyCondition(&getOptions{expect: "Status=Done"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := &fakeClient{err: &api.APIError{Status: 404, Code: "object_not_found", Message: "Could not find page"}}
+	_, err = waitForConsistency(context.Background(), client, "page-id", nil, cond, time.Second)
+	if err == nil || !strings.Contains(err.Error(), "not found or not shared with the integration") {
+		t.Errorf("error = %v, want the not-shared explanation", err)
+	}
+	if !notion.IsNotFound(err) {
+		t.Errorf("error = %v, want it to still match IsNotFound", err)
+	}
+}
+
+func TestBuildConsistencyConditionErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts getOptions
+	}{
+		{name: "no condition", opts: getOptions{}},
+		{name: "expect without value", opts: getOptions{expect: "Status"}},
+		{name: "invalid edited-after", opts: getOptions{editedAfter: "yesterday"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := buildConsistencyCondition(&tt.opts); err == nil {
+				t.Error("buildConsistencyCondition succeeded, want an error")
+			}
+		})
+	}
+}
@@ -2,18 +2,71 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
 
+	"github.com/longkey1/gotion/internal/gotion"
 	"github.com/longkey1/gotion/internal/gotion/config"
 	"github.com/longkey1/gotion/internal/notion"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 type listOptions struct {
 	query    string
 	pageSize int
 	sort     string
+	sortBy   string
 	cursor   string
+	objType  string
+	format   string
+	columns  string
+	fields   string
+	flush    bool
+	all      bool
+	since    string
+	compact  bool
+	limit    int
+	color    string
+	template string
+	parent   string
+	stream   bool
+
+	includeArchived bool
+
+	pageSizeSet bool // --page-size was given explicitly
+}
+
+// listMaxPages caps the number of requests made by --all
+const listMaxPages = 100
+
+// listColumn is a selectable column of table output
+type listColumn struct {
+	header string
+	value  func(page *notion.PageSummary) string
+}
+
+// listColumns are the columns available to --columns
+var listColumns = map[string]listColumn{
+	"title":       {"Title", func(p *notion.PageSummary) string { return p.Title }},
+	"id":          {"ID", func(p *notion.PageSummary) string { return p.ID }},
+	"url":         {"URL", func(p *notion.PageSummary) string { return p.URL }},
+	"object":      {"Object", func(p *notion.PageSummary) string { return p.Object }},
+	"last_edited": {"Last Edited", func(p *notion.PageSummary) string { return p.LastEditedTime }},
+}
+
+// listRecord is a single search result in jsonl output
+type listRecord struct {
+	ID         string          `json:"id"`
+	Object     string          `json:"object,omitempty"`
+	Title      string          `json:"title"`
+	URL        string          `json:"url"`
+	Properties json.RawMessage `json:"properties,omitempty"`
 }
 
 var listOpts = &listOptions{}
@@ -23,6 +76,7 @@ var listCmd = &cobra.Command{
 	Short: "Search and list Notion pages",
 	Long:  `Search for pages in Notion and display the results.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		listOpts.pageSizeSet = cmd.Flags().Changed("page-size")
 		return runList(cmd.Context(), listOpts)
 	},
 }
@@ -31,12 +85,91 @@ func init() {
 	listCmd.Flags().StringVarP(&listOpts.query, "query", "q", "", "Search keyword")
 	listCmd.Flags().IntVarP(&listOpts.pageSize, "page-size", "n", 10, "Number of results to retrieve (max 100)")
 	listCmd.Flags().StringVar(&listOpts.sort, "sort", "descending", "Sort order: ascending, descending")
+	listCmd.Flags().StringVar(&listOpts.sortBy, "sort-by", "edited", "Timestamp to sort by: edited, created (created sorts client-side and requires --all; API backend)")
 	listCmd.Flags().StringVar(&listOpts.cursor, "cursor", "", "Pagination cursor")
+	listCmd.Flags().StringVar(&listOpts.objType, "type", "page", "Object type: page, database, all")
+	listCmd.Flags().StringVarP(&listOpts.format, "format", "o", "json", "Output format: json, jsonl, markdown-table, template")
+	listCmd.Flags().StringVar(&listOpts.template, "template", "", "Go template for --format template, run once per result, e.g. '{{.Title}} {{.URL}}' (fields: ID, Title, URL, LastEdited)")
+	listCmd.Flags().StringVar(&listOpts.columns, "columns", "title,id,last_edited", "Columns for table output: title, id, url, object, last_edited")
+	listCmd.Flags().StringVar(&listOpts.fields, "fields", "", "Fields to output as JSON keys or table columns: title, id, url, object, last_edited (overrides --columns)")
+	listCmd.Flags().StringVar(&listOpts.color, "color", gotion.ColorAuto, "Color markdown-table output: auto, always, never (auto: only on a terminal, unless NO_COLOR is set)")
+	listCmd.Flags().BoolVar(&listOpts.flush, "flush", true, "Flush each jsonl record immediately")
+	listCmd.Flags().StringVar(&listOpts.since, "since", "", "Only results edited after this time (RFC 3339 or 2006-01-02), filtered client-side (API backend)")
+	listCmd.Flags().StringVar(&listOpts.parent, "parent", "", "Only direct children of this page or database, filtered client-side (API backend)")
+	listCmd.Flags().BoolVar(&listOpts.includeArchived, "include-archived", false, "Include archived and trashed results, which are hidden by default (API backend)")
+	listCmd.Flags().BoolVar(&listOpts.compact, "compact", false, "Print json output on a single line instead of indented")
+	listCmd.Flags().BoolVar(&listOpts.all, "all", false, "Fetch all results by following cursors (API backend)")
+	listCmd.Flags().IntVar(&listOpts.limit, "limit", 0, "Fetch results across pages until this many are collected (API backend)")
+	listCmd.Flags().BoolVar(&listOpts.stream, "stream", false, "With --all or --limit, write each fetched page of results before fetching the next (json, jsonl, template)")
+
+	// Accept --output as an alias of --format
+	listCmd.Flags().SetNormalizeFunc(func(f *pflag.FlagSet, name string) pflag.NormalizedName {
+		if name == "output" {
+			name = "format"
+		}
+		return pflag.NormalizedName(name)
+	})
 
 	rootCmd.AddCommand(listCmd)
 }
 
 func runList(ctx context.Context, opts *listOptions) error {
+	switch opts.objType {
+	case "page", "database", "all":
+	default:
+		return fmt.Errorf("unknown type: %s (supported: page, database, all)", opts.objType)
+	}
+
+	switch opts.sortBy {
+	case "edited":
+	case "created":
+		// Search only sorts by last edit, so creation order needs every result
+		if !opts.all || opts.stream || opts.limit > 0 {
+			return fmt.Errorf("--sort-by created requires --all, without --stream or --limit")
+		}
+	default:
+		return fmt.Errorf("unknown sort-by: %s (supported: edited, created)", opts.sortBy)
+	}
+
+	var tmpl *template.Template
+	switch opts.format {
+	case "json", "jsonl", "markdown-table":
+	case "template":
+		var err error
+		tmpl, err = gotion.ParseOutputTemplate(opts.template)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown format: %s (supported: json, jsonl, markdown-table, template)", opts.format)
+	}
+
+	if opts.stream {
+		if !opts.all && opts.limit <= 0 {
+			return fmt.Errorf("--stream requires --all or --limit")
+		}
+		if opts.format == "markdown-table" {
+			return fmt.Errorf("--stream is not supported with markdown-table output, which needs all rows to size its columns")
+		}
+		if opts.format == "json" && opts.fields != "" {
+			return fmt.Errorf("--stream is not supported with --fields and json output; use --format jsonl")
+		}
+	}
+
+	columnSpec := opts.columns
+	if opts.fields != "" {
+		columnSpec = opts.fields
+	}
+	columns, err := parseListColumns(columnSpec)
+	if err != nil {
+		return err
+	}
+
+	color, err := gotion.UseColor(opts.color, os.Stdout)
+	if err != nil {
+		return err
+	}
+
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("failed to load config: %w", err)
@@ -52,6 +185,40 @@ func runList(ctx context.Context, opts *listOptions) error {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
+	if opts.all && cfg.Backend == config.BackendMCP {
+		return fmt.Errorf("--all is not supported with MCP backend")
+	}
+
+	if opts.limit < 0 {
+		return fmt.Errorf("--limit must not be negative")
+	}
+	if opts.limit > 0 && cfg.Backend == config.BackendMCP {
+		return fmt.Errorf("--limit is not supported with MCP backend")
+	}
+
+	var since time.Time
+	if opts.since != "" {
+		if cfg.Backend == config.BackendMCP {
+			return fmt.Errorf("--since is not supported with MCP backend")
+		}
+		since, err = parseSinceDate(opts.since)
+		if err != nil {
+			return err
+		}
+	}
+
+	if opts.parent != "" && cfg.Backend == config.BackendMCP {
+		return fmt.Errorf("--parent is not supported with MCP backend")
+	}
+
+	if opts.includeArchived && cfg.Backend == config.BackendMCP {
+		return fmt.Errorf("--include-archived is not supported with MCP backend")
+	}
+
+	if opts.sortBy != "edited" && cfg.Backend == config.BackendMCP {
+		return fmt.Errorf("--sort-by is not supported with MCP backend")
+	}
+
 	// Validate and clamp page size
 	pageSize := opts.pageSize
 	if pageSize < 1 {
@@ -66,11 +233,60 @@ func runList(ctx context.Context, opts *listOptions) error {
 		PageSize:    pageSize,
 		StartCursor: opts.cursor,
 		Sort:        opts.sort,
+		ObjectType:  opts.objType,
+	}
+
+	// Filters run on each fetched page, so they apply before --limit counts results
+	filter := func(result *notion.SearchResult) error {
+		return filterListResults(result, opts, since)
 	}
 
-	result, err := client.Search(ctx, opts.query, searchOpts)
+	var result *notion.SearchResult
+	if opts.all || opts.limit > 0 {
+		// Fetch as few pages as possible unless --page-size caps each request
+		if opts.all || !opts.pageSizeSet {
+			searchOpts.PageSize = 100
+		}
+		if opts.limit > 0 && opts.limit < searchOpts.PageSize {
+			searchOpts.PageSize = opts.limit
+		}
+		if opts.stream {
+			return streamList(ctx, client, searchOpts, opts, filter, columns, tmpl)
+		}
+		result, err = searchAll(ctx, client, opts.query, searchOpts, opts.limit, filter)
+	} else {
+		result, err = client.Search(ctx, opts.query, searchOpts)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to search: %w", err)
+		return fmt.Errorf("failed to search: %w", explainNotionError(err, "page"))
+	}
+
+	if !opts.all && opts.limit <= 0 {
+		if err := filter(result); err != nil {
+			return err
+		}
+	}
+
+	if opts.sortBy == "created" {
+		if err := sortByCreated(result, opts.sort == "ascending"); err != nil {
+			return err
+		}
+	}
+
+	switch opts.format {
+	case "jsonl":
+		if opts.fields != "" {
+			return writeListFieldsJSONL(result, columns, opts.flush)
+		}
+		return writeListJSONL(result, opts.flush)
+	case "markdown-table":
+		return writeListMarkdownTable(result, columns, color)
+	case "template":
+		return writeListTemplate(result, tmpl)
+	}
+
+	if opts.fields != "" {
+		return writeListFieldsJSON(result, columns, opts.compact)
 	}
 
 	// Format output
@@ -79,6 +295,387 @@ func runList(ctx context.Context, opts *listOptions) error {
 		return err
 	}
 
+	if opts.compact {
+		output, err = gotion.CompactJSON(output)
+		if err != nil {
+			return err
+		}
+	}
+
 	fmt.Print(output)
 	return nil
 }
+
+// searchPages follows NextCursor until all results are fetched, or limit
+// results if limit is positive, calling fn with each page of results. Each
+// page is first passed to filter, if not nil, so only the results it keeps
+// count toward the limit. The last page is trimmed to the limit, and its
+// HasMore reports whether results remain.
+func searchPages(ctx context.Context, client notion.Client, query string, opts *notion.SearchOptions, limit int, filter func(result *notion.SearchResult) error, fn func(result *notion.SearchResult) error) error {
+	count := 0
+	for page := 0; ; page++ {
+		if page == listMaxPages {
+			return fmt.Errorf("stopped after %d pages; narrow the query or use --cursor", listMaxPages)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		result, err := client.Search(ctx, query, opts)
+		if err != nil {
+			return err
+		}
+		if filter != nil {
+			if err := filter(result); err != nil {
+				return err
+			}
+		}
+
+		raw, err := searchRawResults(result)
+		if err != nil {
+			return err
+		}
+
+		if limit > 0 && count+len(raw) >= limit {
+			remaining := limit - count
+			result.HasMore = len(raw) > remaining || result.HasMore
+			result.Pages = result.Pages[:min(len(result.Pages), remaining)]
+			if err := setSearchRawResults(result, raw[:remaining]); err != nil {
+				return err
+			}
+			return fn(result)
+		}
+		count += len(raw)
+
+		if err := fn(result); err != nil {
+			return err
+		}
+
+		if !result.HasMore || result.NextCursor == "" {
+			return nil
+		}
+		opts.StartCursor = result.NextCursor
+	}
+}
+
+// searchAll fetches results with searchPages and merges them into a single
+// result, including a combined raw JSON response
+func searchAll(ctx context.Context, client notion.Client, query string, opts *notion.SearchOptions, limit int, filter func(result *notion.SearchResult) error) (*notion.SearchResult, error) {
+	merged := &notion.SearchResult{}
+	var rawResults []json.RawMessage
+
+	err := searchPages(ctx, client, query, opts, limit, filter, func(result *notion.SearchResult) error {
+		raw, err := searchRawResults(result)
+		if err != nil {
+			return err
+		}
+
+		merged.Source = result.Source
+		merged.Pages = append(merged.Pages, result.Pages...)
+		merged.HasMore = result.HasMore
+		rawResults = append(rawResults, raw...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if rawResults == nil {
+		rawResults = []json.RawMessage{}
+	}
+	rawJSON, err := json.MarshalIndent(map[string]interface{}{
+		"object":      "list",
+		"results":     rawResults,
+		"has_more":    merged.HasMore,
+		"next_cursor": nil,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal search results: %w", err)
+	}
+	merged.RawJSON = rawJSON
+
+	return merged, nil
+}
+
+// searchRawResults returns the results array of a search result's raw JSON
+func searchRawResults(result *notion.SearchResult) ([]json.RawMessage, error) {
+	var raw struct {
+		Results []json.RawMessage `json:"results"`
+	}
+	if err := json.Unmarshal(result.RawJSON, &raw); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal search response: %w", err)
+	}
+	return raw.Results, nil
+}
+
+// setSearchRawResults replaces the results array of a search result's raw JSON
+func setSearchRawResults(result *notion.SearchResult, results []json.RawMessage) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(result.RawJSON, &raw); err != nil {
+		return fmt.Errorf("failed to unmarshal search response: %w", err)
+	}
+
+	if results == nil {
+		results = []json.RawMessage{}
+	}
+	data, err := json.Marshal(results)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search results: %w", err)
+	}
+	raw["results"] = data
+
+	rawJSON, err := json.MarshalIndent(raw, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal search response: %w", err)
+	}
+	result.RawJSON = rawJSON
+	return nil
+}
+
+// parseSinceDate parses an RFC 3339 time or a 2006-01-02 date
+func parseSinceDate(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: must be an RFC 3339 time or a date (2006-01-02)", s)
+}
+
+// filterEditedSince keeps only results edited after since
+func filterEditedSince(result *notion.SearchResult, since time.Time) error {
+	return filterSearchResults(result, func(page *notion.PageSummary) bool {
+		edited, err := time.Parse(time.RFC3339, page.LastEditedTime)
+		return err == nil && edited.After(since)
+	})
+}
+
+// filterListResults applies the client-side filters selected by opts
+func filterListResults(result *notion.SearchResult, opts *listOptions, since time.Time) error {
+	// Search has no server-side filter for archived pages
+	if !opts.includeArchived && result.Source != "mcp" {
+		if err := filterSearchResults(result, func(page *notion.PageSummary) bool {
+			return !page.Archived
+		}); err != nil {
+			return err
+		}
+	}
+
+	if !since.IsZero() {
+		if err := filterEditedSince(result, since); err != nil {
+			return err
+		}
+	}
+
+	if opts.parent != "" {
+		if err := filterByParent(result, gotion.ExtractPageID(opts.parent)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sortByCreated orders results by creation time, in both the summaries and
+// the raw JSON response
+func sortByCreated(result *notion.SearchResult, ascending bool) error {
+	sort.SliceStable(result.Pages, func(i, j int) bool {
+		if ascending {
+			return result.Pages[i].CreatedTime < result.Pages[j].CreatedTime
+		}
+		return result.Pages[i].CreatedTime > result.Pages[j].CreatedTime
+	})
+
+	items, err := searchRawResults(result)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]json.RawMessage, len(items))
+	for _, item := range items {
+		var obj struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(item, &obj); err == nil {
+			byID[obj.ID] = item
+		}
+	}
+
+	sorted := make([]json.RawMessage, 0, len(result.Pages))
+	for _, page := range result.Pages {
+		if item, ok := byID[page.ID]; ok {
+			sorted = append(sorted, item)
+		}
+	}
+	return setSearchRawResults(result, sorted)
+}
+
+// filterByParent keeps only results whose parent is the page or database parentID
+func filterByParent(result *notion.SearchResult, parentID string) error {
+	return filterSearchResults(result, func(page *notion.PageSummary) bool {
+		return page.Parent != nil && page.Parent.ID == parentID
+	})
+}
+
+// filterSearchResults keeps only results for which keep returns true, in both
+// the summaries and the raw JSON response
+func filterSearchResults(result *notion.SearchResult, keep func(page *notion.PageSummary) bool) error {
+	kept := make(map[string]bool)
+	var pages []notion.PageSummary
+	for i := range result.Pages {
+		if keep(&result.Pages[i]) {
+			pages = append(pages, result.Pages[i])
+			kept[result.Pages[i].ID] = true
+		}
+	}
+	result.Pages = pages
+
+	items, err := searchRawResults(result)
+	if err != nil {
+		return err
+	}
+
+	var filtered []json.RawMessage
+	for _, item := range items {
+		var obj struct {
+			ID string `json:"id"`
+		}
+		if err := json.Unmarshal(item, &obj); err == nil && kept[obj.ID] {
+			filtered = append(filtered, item)
+		}
+	}
+	return setSearchRawResults(result, filtered)
+}
+
+// parseListColumns parses a comma-separated list of column names
+func parseListColumns(spec string) ([]string, error) {
+	var columns []string
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if _, ok := listColumns[name]; !ok {
+			return nil, fmt.Errorf("unknown column: %s (supported: title, id, url, object, last_edited)", name)
+		}
+		columns = append(columns, name)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("no columns given")
+	}
+	return columns, nil
+}
+
+func writeListMarkdownTable(result *notion.SearchResult, columns []string, color bool) error {
+	if result.Source == "mcp" {
+		return fmt.Errorf("markdown-table output is not supported with MCP backend")
+	}
+
+	headers := make([]string, len(columns))
+	for i, name := range columns {
+		headers[i] = listColumns[name].header
+	}
+
+	rows := make([][]string, len(result.Pages))
+	for i := range result.Pages {
+		row := make([]string, len(columns))
+		for j, name := range columns {
+			row[j] = listColumns[name].value(&result.Pages[i])
+		}
+		rows[i] = row
+	}
+
+	if color {
+		fmt.Print(gotion.FormatMarkdownTableColor(headers, rows))
+	} else {
+		fmt.Print(gotion.FormatMarkdownTable(headers, rows))
+	}
+	return nil
+}
+
+func writeListTemplate(result *notion.SearchResult, tmpl *template.Template) error {
+	if result.Source == "mcp" {
+		return fmt.Errorf("template output is not supported with MCP backend")
+	}
+
+	for _, page := range result.Pages {
+		output, err := gotion.ExecuteOutputTemplate(tmpl, &gotion.TemplateData{
+			ID:         page.ID,
+			Title:      page.Title,
+			URL:        page.URL,
+			LastEdited: page.LastEditedTime,
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Print(output)
+	}
+	return nil
+}
+
+// listFields projects a search result onto the given fields
+func listFields(page *notion.PageSummary, fields []string) map[string]string {
+	record := make(map[string]string, len(fields))
+	for _, name := range fields {
+		record[name] = listColumns[name].value(page)
+	}
+	return record
+}
+
+func writeListFieldsJSON(result *notion.SearchResult, fields []string, compact bool) error {
+	if result.Source == "mcp" {
+		return fmt.Errorf("--fields is not supported with MCP backend")
+	}
+
+	records := make([]map[string]string, len(result.Pages))
+	for i := range result.Pages {
+		records[i] = listFields(&result.Pages[i], fields)
+	}
+
+	var output []byte
+	var err error
+	if compact {
+		output, err = json.Marshal(records)
+	} else {
+		output, err = json.MarshalIndent(records, "", "  ")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to marshal results: %w", err)
+	}
+	fmt.Println(string(output))
+	return nil
+}
+
+func writeListFieldsJSONL(result *notion.SearchResult, fields []string, flush bool) error {
+	if result.Source == "mcp" {
+		return fmt.Errorf("jsonl output is not supported with MCP backend")
+	}
+
+	w := gotion.NewJSONLWriter(os.Stdout, flush)
+	for i := range result.Pages {
+		if err := w.Write(listFields(&result.Pages[i], fields)); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+func writeListJSONL(result *notion.SearchResult, flush bool) error {
+	if result.Source == "mcp" {
+		return fmt.Errorf("jsonl output is not supported with MCP backend")
+	}
+
+	w := gotion.NewJSONLWriter(os.Stdout, flush)
+	for _, page := range result.Pages {
+		if err := w.Write(&listRecord{
+			ID:         page.ID,
+			Object:     page.Object,
+			Title:      page.Title,
+			URL:        page.URL,
+			Properties: page.Properties,
+		}); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
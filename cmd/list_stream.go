@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/template"
+
+	"github.com/longkey1/gotion/internal/notion"
+)
+
+// streamList writes each page of search results as soon as it is fetched
+// instead of collecting all results first
+func streamList(ctx context.Context, client notion.Client, searchOpts *notion.SearchOptions, opts *listOptions, filter func(result *notion.SearchResult) error, columns []string, tmpl *template.Template) error {
+	var jsonStream *listJSONStream
+	if opts.format == "json" {
+		jsonStream = newListJSONStream(os.Stdout, opts.compact)
+	}
+
+	var hasMore bool
+	err := searchPages(ctx, client, opts.query, searchOpts, opts.limit, filter, func(result *notion.SearchResult) error {
+		hasMore = result.HasMore
+
+		switch opts.format {
+		case "jsonl":
+			if opts.fields != "" {
+				return writeListFieldsJSONL(result, columns, opts.flush)
+			}
+			return writeListJSONL(result, opts.flush)
+		case "template":
+			return writeListTemplate(result, tmpl)
+		default:
+			raw, err := searchRawResults(result)
+			if err != nil {
+				return err
+			}
+			return jsonStream.write(raw)
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to search: %w", explainNotionError(err, "page"))
+	}
+
+	if jsonStream != nil {
+		return jsonStream.close(hasMore)
+	}
+	return nil
+}
+
+// listJSONStream writes the list JSON envelope of json output one result at
+// a time, producing the same document as the non-streaming output
+type listJSONStream struct {
+	w       io.Writer
+	compact bool
+	started bool
+	count   int
+}
+
+func newListJSONStream(w io.Writer, compact bool) *listJSONStream {
+	return &listJSONStream{w: w, compact: compact}
+}
+
+// write writes results, starting the envelope on the first call
+func (s *listJSONStream) write(results []json.RawMessage) error {
+	if err := s.start(); err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		var buf bytes.Buffer
+		sep := ","
+		if s.count == 0 {
+			sep = ""
+		}
+		if s.compact {
+			if err := json.Compact(&buf, result); err != nil {
+				return fmt.Errorf("failed to format result: %w", err)
+			}
+		} else {
+			sep += "\n    "
+			if err := json.Indent(&buf, result, "    ", "  "); err != nil {
+				return fmt.Errorf("failed to format result: %w", err)
+			}
+		}
+		if _, err := fmt.Fprint(s.w, sep+buf.String()); err != nil {
+			return err
+		}
+		s.count++
+	}
+	return nil
+}
+
+func (s *listJSONStream) start() error {
+	if s.started {
+		return nil
+	}
+	s.started = true
+
+	header := "{\n  \"object\": \"list\",\n  \"results\": ["
+	if s.compact {
+		header = `{"object":"list","results":[`
+	}
+	_, err := fmt.Fprint(s.w, header)
+	return err
+}
+
+// close ends the envelope. next_cursor is always null, as all requested results were written.
+func (s *listJSONStream) close(hasMore bool) error {
+	if err := s.start(); err != nil {
+		return err
+	}
+
+	footer := fmt.Sprintf("],\n  \"next_cursor\": null,\n  \"has_more\": %t\n}\n", hasMore)
+	if s.compact {
+		footer = fmt.Sprintf("],\"next_cursor\":null,\"has_more\":%t}\n", hasMore)
+	} else if s.count > 0 {
+		footer = "\n  " + footer
+	}
+	_, err := fmt.Fprint(s.w, footer)
+	return err
+}
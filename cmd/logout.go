@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/longkey1/gotion/internal/gotion/config"
+	"github.com/spf13/cobra"
+)
+
+type logoutOptions struct {
+	yes bool
+}
+
+var logoutOpts = &logoutOptions{}
+
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Delete stored credentials",
+	Long: `Delete the stored OAuth token.
+
+Removes the token from the token store in use (token file or OS keychain,
+see GOTION_TOKEN_STORE). Tokens set via environment variables or
+config.toml are not affected.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runLogout(logoutOpts)
+	},
+}
+
+func init() {
+	logoutCmd.Flags().BoolVarP(&logoutOpts.yes, "yes", "y", false, "Skip confirmation prompt")
+	rootCmd.AddCommand(logoutCmd)
+}
+
+func runLogout(opts *logoutOptions) error {
+	if !config.TokenExists() {
+		fmt.Println("No stored credentials found. Nothing to do.")
+		return nil
+	}
+
+	tokenLocation, _ := config.TokenLocation()
+
+	if !opts.yes {
+		fmt.Printf("Delete stored credentials in %s? [y/N]: ", tokenLocation)
+		var response string
+		fmt.Scanln(&response)
+		if response != "y" && response != "Y" {
+			fmt.Println("Cancelled.")
+			return nil
+		}
+	}
+
+	if err := config.DeleteToken(); err != nil {
+		return err
+	}
+	if err := config.DeleteMCPSession(); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed credentials: %s\n", tokenLocation)
+	return nil
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/longkey1/gotion/internal/gotion/config"
+	"github.com/longkey1/gotion/internal/notion"
+	"github.com/longkey1/gotion/internal/notion/mcp"
+	"github.com/spf13/cobra"
+)
+
+type mcpCallOptions struct {
+	args   string
+	replay string
+}
+
+var mcpCallOpts = &mcpCallOptions{}
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Advanced MCP server access",
+	Long: `Advanced access to the Notion MCP server.
+
+Requires MCP backend.`,
+}
+
+var mcpCallCmd = &cobra.Command{
+	Use:   "call <tool>",
+	Short: "Call an MCP tool and print its result",
+	Long: `Call any tool of the Notion MCP server with arbitrary JSON arguments
+and print the raw tool result content. This exposes tools that have no
+gotion command of their own.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runMCPCall(cmd.Context(), args[0], mcpCallOpts)
+	},
+}
+
+func init() {
+	mcpCallCmd.Flags().StringVar(&mcpCallOpts.args, "args", "{}", "Tool arguments as a JSON object")
+
+	// Debugging only: answers from a GOTION_MCP_TRACE file instead of the server
+	mcpCallCmd.Flags().StringVar(&mcpCallOpts.replay, "replay", "", "Replay recorded responses from a trace file (offline, no token needed)")
+	_ = mcpCallCmd.Flags().MarkHidden("replay")
+
+	mcpCmd.AddCommand(mcpCallCmd)
+	rootCmd.AddCommand(mcpCmd)
+}
+
+func runMCPCall(ctx context.Context, tool string, opts *mcpCallOptions) error {
+	var args map[string]interface{}
+	if err := json.Unmarshal([]byte(opts.args), &args); err != nil {
+		return fmt.Errorf("invalid --args (must be a JSON object): %w", err)
+	}
+
+	if opts.replay != "" {
+		client, err := mcp.NewClient("")
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+		if err := client.Replay(opts.replay); err != nil {
+			return err
+		}
+		return printMCPToolResult(ctx, client, tool, args)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	if cfg.Backend != config.BackendMCP {
+		return fmt.Errorf("mcp call requires the MCP backend (use --backend mcp)")
+	}
+
+	client, err := notion.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	return printMCPToolResult(ctx, client.(*mcp.Client), tool, args)
+}
+
+func printMCPToolResult(ctx context.Context, client *mcp.Client, tool string, args map[string]interface{}) error {
+	content, err := client.CallTool(ctx, tool, args)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(content))
+	return nil
+}
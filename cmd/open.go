@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/longkey1/gotion/internal/gotion"
+	"github.com/longkey1/gotion/internal/gotion/config"
+	"github.com/longkey1/gotion/internal/notion"
+	"github.com/spf13/cobra"
+)
+
+type openOptions struct {
+	public bool
+}
+
+var openOpts = &openOptions{}
+
+var openCmd = &cobra.Command{
+	Use:   "open <page_id>",
+	Short: "Open a Notion page in the browser",
+	Long: `Open a Notion page in the default browser.
+
+With --public, the page's public web URL is opened instead of the workspace
+URL. The page must be published to the web.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runOpen(cmd.Context(), args[0], openOpts)
+	},
+}
+
+func init() {
+	openCmd.Flags().BoolVar(&openOpts.public, "public", false, "Open the public web URL (API backend)")
+	rootCmd.AddCommand(openCmd)
+}
+
+func runOpen(ctx context.Context, pageIDOrURL string, opts *openOptions) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	client, err := notion.NewClient(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	pageID := gotion.ExtractPageID(pageIDOrURL)
+
+	result, err := client.GetPage(ctx, pageID, &notion.GetPageOptions{SkipChildren: true})
+	if err != nil {
+		return fmt.Errorf("failed to get page: %w", err)
+	}
+
+	url := result.URL
+	if opts.public {
+		if result.Source == "mcp" {
+			return fmt.Errorf("--public is not supported with MCP backend")
+		}
+		if result.PublicURL == "" {
+			return fmt.Errorf("page is not published to the web")
+		}
+		url = result.PublicURL
+	}
+
+	if url == "" {
+		return fmt.Errorf("page has no URL")
+	}
+
+	fmt.Println(url)
+	if err := openBrowser(url); err != nil {
+		return fmt.Errorf("failed to open browser: %w", err)
+	}
+	return nil
+}
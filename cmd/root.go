@@ -2,19 +2,96 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"os"
 	"time"
 
 	"github.com/longkey1/gotion/internal/gotion/config"
+	"github.com/longkey1/gotion/internal/notion/api"
+	"github.com/longkey1/gotion/internal/notion/cache"
 	"github.com/longkey1/gotion/internal/notion/mcp"
+	"github.com/longkey1/gotion/internal/notion/ratelimit"
+	"github.com/longkey1/gotion/internal/notion/transport"
 	"github.com/spf13/cobra"
 )
 
+type rootOptions struct {
+	profile  string
+	backend  string
+	timeout  time.Duration
+	verbose  bool
+	insecure bool
+
+	rateLimit      float64
+	rateLimitBurst int
+	cacheTTL       time.Duration
+}
+
+var rootOpts = &rootOptions{}
+
+// cancelTimeout releases the --timeout context once the command finishes
+var cancelTimeout context.CancelFunc = func() {}
+
 var rootCmd = &cobra.Command{
 	Use:   "gotion",
 	Short: "A CLI tool for Notion API",
 	Long:  `gotion is a command-line interface for interacting with the Notion API.`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		if rootOpts.backend != "" {
+			if err := config.SetBackend(config.Backend(rootOpts.backend)); err != nil {
+				return err
+			}
+		}
+
+		if rootOpts.verbose {
+			slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug})))
+		}
+
+		if rootOpts.profile != "" {
+			if err := config.SetProfile(rootOpts.profile); err != nil {
+				return err
+			}
+		}
+
+		if rootOpts.insecure {
+			fmt.Fprintln(os.Stderr, "Warning: TLS certificate verification is disabled (--insecure)")
+		}
+		if err := transport.ConfigureTLS(os.Getenv(transport.CABundleEnv), rootOpts.insecure); err != nil {
+			return err
+		}
+		if err := transport.ConfigureProxy(os.Getenv(transport.ProxyEnv)); err != nil {
+			return err
+		}
+		mcp.ConfigureTrace(os.Getenv(mcp.TraceEnv))
+
+		if cmd.Flags().Changed("rate-limit-burst") && rootOpts.rateLimitBurst <= 0 {
+			return fmt.Errorf("--rate-limit-burst must be positive")
+		}
+		if rootOpts.rateLimit > 0 {
+			limiter, err := ratelimit.New(rootOpts.rateLimit, rootOpts.rateLimitBurst)
+			if err != nil {
+				return err
+			}
+			ratelimit.Default = limiter
+		}
+
+		if rootOpts.cacheTTL < 0 {
+			return fmt.Errorf("--cache-ttl must not be negative")
+		}
+		if rootOpts.cacheTTL > 0 {
+			cache.Default = cache.NewLRU(cache.DefaultSize, rootOpts.cacheTTL)
+		}
+
+		// Bound the whole command by --timeout. Commands that wait for the user
+		// are exempt, and serve applies the timeout to each request instead.
+		if rootOpts.timeout > 0 && !waitsForUser(cmd) && cmd != serveCmd {
+			ctx, cancel := context.WithTimeout(cmd.Context(), rootOpts.timeout)
+			cancelTimeout = cancel
+			cmd.SetContext(ctx)
+		}
+
 		// Skip token refresh for non-API commands
 		if skipTokenRefresh(cmd) {
 			return nil
@@ -30,24 +107,56 @@ var rootCmd = &cobra.Command{
 
 // Execute runs the root command
 func Execute() error {
+	defer func() { cancelTimeout() }()
 	return rootCmd.Execute()
 }
 
 func init() {
-	// Global flags can be added here if needed
+	rootCmd.PersistentFlags().StringVar(&rootOpts.profile, "profile", "", "Named profile to use (env: GOTION_PROFILE)")
+	rootCmd.PersistentFlags().StringVar(&rootOpts.backend, "backend", "", "Backend to use for this command: api, mcp (overrides config)")
+	rootCmd.PersistentFlags().BoolVarP(&rootOpts.verbose, "verbose", "v", false, "Log HTTP requests to stderr")
+	rootCmd.PersistentFlags().Float64Var(&rootOpts.rateLimit, "rate-limit", 0, "Maximum Notion API requests per second (0 for no limit)")
+	rootCmd.PersistentFlags().IntVar(&rootOpts.rateLimitBurst, "rate-limit-burst", 0, "Requests allowed at once before --rate-limit applies (default: the --rate-limit value)")
+	rootCmd.PersistentFlags().DurationVar(&rootOpts.cacheTTL, "cache-ttl", 0, "Cache pages and search results in memory for this long (0 to disable, API backend)")
+	rootCmd.PersistentFlags().DurationVar(&rootOpts.timeout, "timeout", 30*time.Second, "Timeout for the whole command, except while waiting for the user (0 for none)")
+
+	// Testing only: disables TLS certificate verification
+	rootCmd.PersistentFlags().BoolVar(&rootOpts.insecure, "insecure", false, "Skip TLS certificate verification (testing only)")
+	_ = rootCmd.PersistentFlags().MarkHidden("insecure")
+}
+
+// waitsForUser reports whether cmd may wait on a confirmation prompt or the
+// browser sign-in, which --timeout must not cut short
+func waitsForUser(cmd *cobra.Command) bool {
+	switch cmd {
+	case authCmd:
+		return true
+	case deleteCmd:
+		return !deleteOpts.yes && !deleteOpts.dryRun
+	case logoutCmd:
+		return !logoutOpts.yes
+	}
+	return false
 }
 
 // skipTokenRefresh returns true if the command should not trigger token refresh
 func skipTokenRefresh(cmd *cobra.Command) bool {
 	for c := cmd; c != nil; c = c.Parent() {
 		switch c.Name() {
-		case "auth", "config", "version", "help", "completion":
+		case "auth", "logout", "config", "cache", "version", "help", "completion":
 			return true
 		}
 	}
 	return false
 }
 
+// errTokenRefresh is matched by errors from refreshing an expired token
+var errTokenRefresh = errors.New("token refresh failed")
+
+// clockSkewMargin is how far past expiry a just-refreshed token must be
+// before it is reported as a sign of clock skew
+const clockSkewMargin = 10 * time.Minute
+
 // refreshTokenIfNeeded checks and refreshes the token if expired
 func refreshTokenIfNeeded() error {
 	tokenData, err := config.LoadToken()
@@ -56,29 +165,14 @@ func refreshTokenIfNeeded() error {
 		return nil
 	}
 
-	if !tokenData.NeedsRefresh() {
+	if !tokenData.NeedsRefresh(time.Now()) {
 		return nil
 	}
 
-	// Determine backend: check token data first, then config
-	backend := tokenData.Backend
-	if backend == "" {
-		cfg, err := config.Load()
-		if err != nil {
-			return nil
-		}
-		backend = cfg.Backend
-	}
-
-	if backend != config.BackendMCP {
-		return nil
-	}
-
-	// Refresh MCP token
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	newToken, err := mcp.RefreshToken(ctx, tokenData.ClientID, tokenData.RefreshToken)
+	refreshedData, err := refreshToken(ctx, tokenData)
 	if err != nil {
 		// Re-read token file: another process may have already refreshed it
 		reloaded, reloadErr := config.LoadToken()
@@ -86,24 +180,83 @@ func refreshTokenIfNeeded() error {
 			// Token was refreshed by another process, use it
 			return nil
 		}
-		return fmt.Errorf("token refresh failed (re-authenticate with 'gotion auth'): %w", err)
+		return fmt.Errorf("%w (re-authenticate with 'gotion auth'): %w", errTokenRefresh, err)
+	}
+
+	if clockSkewed(refreshedData, time.Now()) {
+		fmt.Fprintf(os.Stderr, "Warning: the refreshed token already expired at %s; check the system clock for skew\n",
+			time.Unix(refreshedData.ExpiresAt, 0).Format(time.RFC3339))
+	}
+
+	// Save the refreshed token
+	return config.SaveToken(refreshedData)
+}
+
+// clockSkewed reports whether a token refreshed at now is already long
+// expired, which means the system clock is off and every command would
+// refresh again
+func clockSkewed(refreshed *config.TokenData, now time.Time) bool {
+	return refreshed.IsTokenExpired(now.Add(-clockSkewMargin))
+}
+
+// refreshToken exchanges the refresh token of tokenData for a new token
+func refreshToken(ctx context.Context, tokenData *config.TokenData) (*config.TokenData, error) {
+	// Determine backend: check token data first, then config
+	backend := tokenData.Backend
+	if backend == "" {
+		cfg, err := config.Load()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load config: %w", err)
+		}
+		backend = cfg.Backend
 	}
 
-	// Update token data
-	refreshedData := &config.TokenData{
-		Backend:      config.BackendMCP,
-		AccessToken:  newToken.AccessToken,
-		TokenType:    newToken.TokenType,
-		ClientID:     tokenData.ClientID,
-		RefreshToken: newToken.RefreshToken,
-		ExpiresAt:    newToken.ExpiresAt,
+	var refreshedData *config.TokenData
+	switch backend {
+	case config.BackendMCP:
+		newToken, err := mcp.RefreshToken(ctx, tokenData.ClientID, tokenData.RefreshToken)
+		if err != nil {
+			return nil, err
+		}
+		refreshedData = &config.TokenData{
+			Backend:      config.BackendMCP,
+			AccessToken:  newToken.AccessToken,
+			TokenType:    newToken.TokenType,
+			ClientID:     tokenData.ClientID,
+			RefreshToken: newToken.RefreshToken,
+			ExpiresAt:    newToken.ExpiresAt,
+		}
+	case config.BackendAPI, "":
+		oauthCfg, err := config.LoadOAuthConfig()
+		if err != nil {
+			return nil, fmt.Errorf("failed to load OAuth config: %w", err)
+		}
+		if err := oauthCfg.ValidateOAuth(); err != nil {
+			return nil, err
+		}
+		newToken, err := api.RefreshToken(ctx, oauthCfg.ClientID, oauthCfg.ClientSecret, tokenData.RefreshToken)
+		if err != nil {
+			return nil, err
+		}
+		refreshedData = &config.TokenData{
+			Backend:       config.BackendAPI,
+			AccessToken:   newToken.AccessToken,
+			TokenType:     newToken.TokenType,
+			BotID:         tokenData.BotID,
+			WorkspaceID:   tokenData.WorkspaceID,
+			WorkspaceName: tokenData.WorkspaceName,
+			RefreshToken:  newToken.RefreshToken,
+		}
+		if !newToken.ExpiresAt.IsZero() {
+			refreshedData.ExpiresAt = newToken.ExpiresAt.Unix()
+		}
+	default:
+		return nil, backend.Validate()
 	}
 
 	// Keep refresh token if new one is not provided
 	if refreshedData.RefreshToken == "" {
 		refreshedData.RefreshToken = tokenData.RefreshToken
 	}
-
-	// Save the refreshed token
-	return config.SaveToken(refreshedData)
+	return refreshedData, nil
 }
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/longkey1/gotion/internal/gotion/config"
+	"github.com/spf13/cobra"
+)
+
+func TestClockSkewed(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		expiresAt time.Time
+		want      bool
+	}{
+		{name: "fresh token", expiresAt: now.Add(time.Hour), want: false},
+		{name: "within refresh skew", expiresAt: now.Add(time.Minute), want: false},
+		{name: "just expired", expiresAt: now.Add(-time.Minute), want: false},
+		{name: "clock hours ahead", expiresAt: now.Add(-3 * time.Hour), want: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := &config.TokenData{RefreshToken: "r", ExpiresAt: tt.expiresAt.Unix()}
+			if got := clockSkewed(token, now); got != tt.want {
+				t.Errorf("clockSkewed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// No expiry information never looks skewed
+	if clockSkewed(&config.TokenData{RefreshToken: "r"}, now) {
+		t.Error("clockSkewed = true for a token without expiry")
+	}
+}
+
+func TestNeedsRefreshWithSkewedClock(t *testing.T) {
+	issued := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	token := &config.TokenData{RefreshToken: "r", ExpiresAt: issued.Add(time.Hour).Unix()}
+
+	// A clock running behind keeps using the token; one running ahead refreshes it
+	if token.NeedsRefresh(issued.Add(-2 * time.Hour)) {
+		t.Error("NeedsRefresh = true with the clock behind")
+	}
+	if !token.NeedsRefresh(issued.Add(2 * time.Hour)) {
+		t.Error("NeedsRefresh = false with the clock ahead")
+	}
+}
+
+func TestWaitsForUser(t *testing.T) {
+	tests := []struct {
+		name string
+		cmd  *cobra.Command
+		yes  bool
+		want bool
+	}{
+		{name: "auth", cmd: authCmd, want: true},
+		{name: "auth refresh", cmd: authRefreshCmd, want: false},
+		{name: "auth set-token", cmd: authSetTokenCmd, want: false},
+		{name: "auth migrate", cmd: authMigrateCmd, want: false},
+		{name: "delete with prompt", cmd: deleteCmd, want: true},
+		{name: "delete --yes", cmd: deleteCmd, yes: true, want: false},
+		{name: "logout with prompt", cmd: logoutCmd, want: true},
+		{name: "logout --yes", cmd: logoutCmd, yes: true, want: false},
+		{name: "get", cmd: getCmd, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deleteOpts.yes, logoutOpts.yes = tt.yes, tt.yes
+			t.Cleanup(func() { deleteOpts.yes, logoutOpts.yes = false, false })
+
+			if got := waitsForUser(tt.cmd); got != tt.want {
+				t.Errorf("waitsForUser = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimeoutDefault(t *testing.T) {
+	flag := rootCmd.PersistentFlags().Lookup("timeout")
+	if flag == nil || flag.DefValue != "30s" {
+		t.Errorf("--timeout default = %v, want 30s", flag)
+	}
+}
@@ -0,0 +1,267 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/longkey1/gotion/internal/gotion"
+	"github.com/longkey1/gotion/internal/gotion/config"
+	"github.com/longkey1/gotion/internal/notion"
+	"github.com/longkey1/gotion/internal/notion/metrics"
+	"github.com/spf13/cobra"
+)
+
+type serveOptions struct {
+	addr    string
+	metrics bool
+}
+
+var serveOpts = &serveOptions{}
+
+// serveShutdownTimeout bounds how long in-flight requests may finish after SIGINT
+const serveShutdownTimeout = 10 * time.Second
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve Notion pages over a local HTTP API",
+	Long: `Run a local HTTP server that answers requests with the configured
+backend and token, refreshing the token as needed.
+
+Endpoints:
+  GET /pages/{id}    Page JSON, as printed by 'gotion get'
+  GET /search?q=     Search results JSON, as printed by 'gotion list'
+                     (optional: cursor, page_size)
+  GET /metrics       Prometheus metrics (with --metrics)
+
+--timeout applies to each request. Stop the server with Ctrl-C.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runServe(cmd.Context(), serveOpts)
+	},
+}
+
+func init() {
+	serveCmd.Flags().StringVar(&serveOpts.addr, "addr", "127.0.0.1:8090", "Address to listen on")
+	serveCmd.Flags().BoolVar(&serveOpts.metrics, "metrics", false, "Expose Prometheus metrics at /metrics")
+
+	rootCmd.AddCommand(serveCmd)
+}
+
+func runServe(ctx context.Context, opts *serveOptions) error {
+	s := &pageServer{}
+
+	// Fail on startup rather than on the first request
+	if _, err := s.client(); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /pages/{id}", s.handlePage)
+	mux.HandleFunc("GET /search", s.handleSearch)
+	if opts.metrics {
+		mux.HandleFunc("GET /metrics", handleMetrics)
+	}
+
+	server := &http.Server{
+		Addr:              opts.addr,
+		Handler:           countRequests(mux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	fmt.Fprintf(os.Stderr, "Listening on %s\n", opts.addr)
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("failed to serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	fmt.Fprintln(os.Stderr, "Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("failed to shut down server: %w", err)
+	}
+	return nil
+}
+
+// pageServer answers HTTP requests with a Notion client
+type pageServer struct {
+	mu sync.Mutex
+}
+
+// client refreshes the token if needed and creates a client, so that a
+// long-running server keeps working after the token expires
+func (s *pageServer) client() (notion.Client, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := refreshTokenIfNeeded(); err != nil {
+		return nil, err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	client, err := notion.NewClient(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+	return client, nil
+}
+
+// requestContext bounds a request by --timeout
+func (s *pageServer) requestContext(r *http.Request) (context.Context, context.CancelFunc) {
+	if rootOpts.timeout > 0 {
+		return context.WithTimeout(r.Context(), rootOpts.timeout)
+	}
+	return context.WithCancel(r.Context())
+}
+
+func (s *pageServer) handlePage(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := s.requestContext(r)
+	defer cancel()
+
+	client, err := s.client()
+	if err != nil {
+		writeServeError(w, serveErrorStatus(err), err)
+		return
+	}
+
+	result, err := client.GetPage(ctx, gotion.ExtractPageID(r.PathValue("id")), nil)
+	if err != nil {
+		writeServeError(w, serveErrorStatus(err), err)
+		return
+	}
+
+	output, err := client.FormatPage(result)
+	if err != nil {
+		writeServeError(w, serveErrorStatus(err), err)
+		return
+	}
+	writeServeJSON(w, output)
+}
+
+func (s *pageServer) handleSearch(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := s.requestContext(r)
+	defer cancel()
+
+	query := r.URL.Query()
+	searchOpts := &notion.SearchOptions{
+		StartCursor: query.Get("cursor"),
+		Sort:        "descending",
+	}
+	if v := query.Get("page_size"); v != "" {
+		pageSize, err := strconv.Atoi(v)
+		if err != nil || pageSize < 1 || pageSize > 100 {
+			writeServeError(w, http.StatusBadRequest, fmt.Errorf("page_size must be between 1 and 100"))
+			return
+		}
+		searchOpts.PageSize = pageSize
+	}
+
+	client, err := s.client()
+	if err != nil {
+		writeServeError(w, serveErrorStatus(err), err)
+		return
+	}
+
+	result, err := client.Search(ctx, query.Get("q"), searchOpts)
+	if err != nil {
+		writeServeError(w, serveErrorStatus(err), err)
+		return
+	}
+
+	output, err := client.FormatSearch(result)
+	if err != nil {
+		writeServeError(w, serveErrorStatus(err), err)
+		return
+	}
+	writeServeJSON(w, output)
+}
+
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	if err := metrics.WriteText(w); err != nil {
+		slog.Debug("failed to write metrics", "error", err)
+	}
+}
+
+// statusRecorder remembers the status code written to a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// countRequests counts requests by route pattern and status code
+func countRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		// The mux sets the pattern; unmatched paths share one label
+		path := r.Pattern
+		if path == "" {
+			path = "other"
+		}
+		metrics.ServeRequests.Inc(path, strconv.Itoa(rec.status))
+	})
+}
+
+func writeServeJSON(w http.ResponseWriter, output string) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(output))
+}
+
+// serveErrorStatus returns the HTTP status for an error, mirroring ExitCode
+func serveErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, config.ErrInvalidConfig), errors.Is(err, errTokenRefresh), notion.IsUnauthorized(err):
+		return http.StatusUnauthorized
+	case notion.IsNotFound(err):
+		return http.StatusNotFound
+	case notion.IsRateLimited(err):
+		return http.StatusTooManyRequests
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// writeServeError writes err as a JSON error object
+func writeServeError(w http.ResponseWriter, status int, err error) {
+	slog.Debug("serve request failed", "status", status, "error", err)
+
+	body, _ := json.Marshal(map[string]string{"error": err.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(append(body, '\n'))
+}
@@ -16,6 +16,7 @@ type updateOptions struct {
 	file           string
 	propertiesOnly bool
 	contentOnly    bool
+	dryRun         bool
 }
 
 var updateOpts = &updateOptions{}
@@ -41,6 +42,7 @@ func init() {
 	updateCmd.Flags().StringVar(&updateOpts.file, "file", "", "Input file path (default: stdin)")
 	updateCmd.Flags().BoolVar(&updateOpts.propertiesOnly, "properties-only", false, "Update properties only")
 	updateCmd.Flags().BoolVar(&updateOpts.contentOnly, "content-only", false, "Update content only")
+	updateCmd.Flags().BoolVar(&updateOpts.dryRun, "dry-run", false, "Print the requests that would be sent without sending them")
 
 	rootCmd.AddCommand(updateCmd)
 }
@@ -97,12 +99,19 @@ func runUpdate(ctx context.Context, pageIDOrURL string, opts *updateOptions) err
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
+	if opts.dryRun {
+		ctx = notion.WithDryRun(ctx, os.Stdout)
+	}
+
 	// Update page
 	result, err := client.UpdatePage(ctx, pageID, updatePageOpts)
 	if err != nil {
 		return fmt.Errorf("failed to update page: %w", err)
 	}
 
+	if opts.dryRun {
+		return nil
+	}
 	fmt.Println(string(result.RawJSON))
 	return nil
 }
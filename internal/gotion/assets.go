@@ -0,0 +1,104 @@
+package gotion
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Asset is a file referenced by a page: an image, file or pdf block, or an
+// entry of a files property
+type Asset struct {
+	URL      string
+	Name     string // File name given in Notion, if any
+	Property string // Name of the files property; empty for blocks
+}
+
+// assetFile is a Notion file object, hosted externally or by Notion
+type assetFile struct {
+	Name     string `json:"name"`
+	External *struct {
+		URL string `json:"url"`
+	} `json:"external"`
+	File *struct {
+		URL string `json:"url"`
+	} `json:"file"`
+}
+
+func (f *assetFile) url() string {
+	switch {
+	case f == nil:
+		return ""
+	case f.External != nil:
+		return f.External.URL
+	case f.File != nil:
+		return f.File.URL
+	}
+	return ""
+}
+
+type assetBlock struct {
+	Type     string            `json:"type"`
+	Image    *assetFile        `json:"image"`
+	File     *assetFile        `json:"file"`
+	PDF      *assetFile        `json:"pdf"`
+	Children []json.RawMessage `json:"children"`
+}
+
+// PageAssets returns the assets of a combined {page, blocks} JSON object:
+// files properties sorted by name, then blocks in document order
+func PageAssets(rawJSON []byte) ([]Asset, error) {
+	var combined struct {
+		Page struct {
+			Properties map[string]struct {
+				Type  string      `json:"type"`
+				Files []assetFile `json:"files"`
+			} `json:"properties"`
+		} `json:"page"`
+		Blocks []json.RawMessage `json:"blocks"`
+	}
+	if err := json.Unmarshal(rawJSON, &combined); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal page: %w", err)
+	}
+
+	var assets []Asset
+	for _, name := range slices.Sorted(maps.Keys(combined.Page.Properties)) {
+		prop := combined.Page.Properties[name]
+		if prop.Type != "files" {
+			continue
+		}
+		for _, f := range prop.Files {
+			if u := f.url(); u != "" {
+				assets = append(assets, Asset{URL: u, Name: f.Name, Property: name})
+			}
+		}
+	}
+	return appendBlockAssets(assets, combined.Blocks), nil
+}
+
+// appendBlockAssets appends the assets of blocks and their children
+func appendBlockAssets(assets []Asset, blocks []json.RawMessage) []Asset {
+	for _, raw := range blocks {
+		var block assetBlock
+		if err := json.Unmarshal(raw, &block); err != nil {
+			continue
+		}
+
+		var f *assetFile
+		switch block.Type {
+		case "image":
+			f = block.Image
+		case "file":
+			f = block.File
+		case "pdf":
+			f = block.PDF
+		}
+		if u := f.url(); u != "" {
+			assets = append(assets, Asset{URL: u, Name: f.Name})
+		}
+
+		assets = appendBlockAssets(assets, block.Children)
+	}
+	return assets
+}
@@ -0,0 +1,45 @@
+package gotion
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPageAssets(t *testing.T) {
+	raw := []byte(`{
+		"page": {
+			"properties": {
+				"Name": {"type": "title", "title": []},
+				"Attachments": {"type": "files", "files": [
+					{"name": "spec.pdf", "type": "file", "file": {"url": "https://s3.example.com/spec.pdf?sig=1"}},
+					{"name": "", "type": "external", "external": {"url": "https://example.com/logo.svg"}}
+				]}
+			}
+		},
+		"blocks": [
+			{"type": "paragraph", "paragraph": {"rich_text": []}},
+			{"type": "image", "image": {"type": "external", "external": {"url": "https://example.com/cat.png"}}},
+			{"type": "toggle", "has_children": true, "toggle": {"rich_text": []}, "children": [
+				{"type": "pdf", "pdf": {"type": "file", "file": {"url": "https://s3.example.com/paper.pdf"}}},
+				{"type": "file", "file": {"type": "file", "name": "data.csv", "file": {"url": "https://s3.example.com/data.csv"}}}
+			]},
+			{"type": "bookmark", "bookmark": {"url": "https://example.com"}}
+		]
+	}`)
+
+	got, err := PageAssets(raw)
+	if err != nil {
+		t.Fatalf("PageAssets: %v", err)
+	}
+
+	want := []Asset{
+		{URL: "https://s3.example.com/spec.pdf?sig=1", Name: "spec.pdf", Property: "Attachments"},
+		{URL: "https://example.com/logo.svg", Property: "Attachments"},
+		{URL: "https://example.com/cat.png"},
+		{URL: "https://s3.example.com/paper.pdf"},
+		{URL: "https://s3.example.com/data.csv", Name: "data.csv"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("PageAssets =\n%+v\nwant\n%+v", got, want)
+	}
+}
@@ -5,88 +5,59 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"net/url"
+	"strings"
 )
 
 // CallbackServer handles the OAuth callback
 type CallbackServer struct {
-	port     int
-	listener net.Listener
-	code     string
-	state    string
-	err      error
-	done     chan struct{}
+	port      int
+	listeners []net.Listener
+	code      string
+	state     string
+	err       error
+	done      chan struct{}
 }
 
 // NewCallbackServer creates a new callback server
 func NewCallbackServer(port int) (*CallbackServer, error) {
-	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	// Listen on loopback only so the callback is not exposed on the network
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", port))
 	if err != nil {
 		return nil, fmt.Errorf("failed to start callback server: %w", err)
 	}
+	listeners := []net.Listener{listener}
+
+	// localhost may resolve to ::1 first, so also listen there on the same port.
+	// This is best-effort: IPv6 may be disabled.
+	actualPort := listener.Addr().(*net.TCPAddr).Port
+	if listener6, err := net.Listen("tcp", fmt.Sprintf("[::1]:%d", actualPort)); err == nil {
+		listeners = append(listeners, listener6)
+	}
 
 	return &CallbackServer{
-		port:     port,
-		listener: listener,
-		done:     make(chan struct{}),
+		port:      port,
+		listeners: listeners,
+		done:      make(chan struct{}),
 	}, nil
 }
 
 // Port returns the actual port the server is listening on
 func (s *CallbackServer) Port() int {
-	return s.listener.Addr().(*net.TCPAddr).Port
+	return s.listeners[0].Addr().(*net.TCPAddr).Port
 }
 
 // Start starts the callback server and waits for the callback
 func (s *CallbackServer) Start(ctx context.Context, expectedState string) error {
 	server := &http.Server{
-		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if r.URL.Path != "/callback" {
-				http.NotFound(w, r)
-				return
-			}
-
-			query := r.URL.Query()
-
-			// Check for error
-			if errCode := query.Get("error"); errCode != "" {
-				s.err = fmt.Errorf("OAuth error: %s", errCode)
-				w.Header().Set("Content-Type", "text/html")
-				fmt.Fprintf(w, `<html><body><h1>Authentication Failed</h1><p>%s</p><p>You can close this window.</p></body></html>`, errCode)
-				close(s.done)
-				return
-			}
-
-			// Verify state
-			state := query.Get("state")
-			if expectedState != "" && state != expectedState {
-				s.err = fmt.Errorf("state mismatch")
-				w.Header().Set("Content-Type", "text/html")
-				fmt.Fprint(w, `<html><body><h1>Authentication Failed</h1><p>State mismatch</p><p>You can close this window.</p></body></html>`)
-				close(s.done)
-				return
-			}
-
-			// Get authorization code
-			code := query.Get("code")
-			if code == "" {
-				s.err = fmt.Errorf("no authorization code received")
-				w.Header().Set("Content-Type", "text/html")
-				fmt.Fprint(w, `<html><body><h1>Authentication Failed</h1><p>No authorization code received</p><p>You can close this window.</p></body></html>`)
-				close(s.done)
-				return
-			}
-
-			s.code = code
-			s.state = state
-			w.Header().Set("Content-Type", "text/html")
-			fmt.Fprint(w, `<html><body><h1>Authentication Successful!</h1><p>You can close this window and return to the terminal.</p></body></html>`)
-			close(s.done)
-		}),
+		Handler: s.handler(expectedState),
 	}
 
-	go func() {
-		_ = server.Serve(s.listener)
-	}()
+	for _, listener := range s.listeners {
+		go func() {
+			_ = server.Serve(listener)
+		}()
+	}
 
 	select {
 	case <-ctx.Done():
@@ -98,12 +69,94 @@ func (s *CallbackServer) Start(ctx context.Context, expectedState string) error
 	}
 }
 
+// handler handles the OAuth redirect, recording the code or the error and
+// closing s.done once a callback arrives
+func (s *CallbackServer) handler(expectedState string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/callback" {
+			http.NotFound(w, r)
+			return
+		}
+
+		query := r.URL.Query()
+
+		// Check for error
+		if errCode := query.Get("error"); errCode != "" {
+			s.err = fmt.Errorf("OAuth error: %s", errCode)
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprintf(w, `<html><body><h1>Authentication Failed</h1><p>%s</p><p>You can close this window.</p></body></html>`, errCode)
+			close(s.done)
+			return
+		}
+
+		// Verify state
+		state := query.Get("state")
+		if expectedState != "" && state != expectedState {
+			s.err = fmt.Errorf("state mismatch")
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<html><body><h1>Authentication Failed</h1><p>State mismatch</p><p>You can close this window.</p></body></html>`)
+			close(s.done)
+			return
+		}
+
+		// Get authorization code
+		code := query.Get("code")
+		if code == "" {
+			s.err = fmt.Errorf("no authorization code received")
+			w.Header().Set("Content-Type", "text/html")
+			fmt.Fprint(w, `<html><body><h1>Authentication Failed</h1><p>No authorization code received</p><p>You can close this window.</p></body></html>`)
+			close(s.done)
+			return
+		}
+
+		s.code = code
+		s.state = state
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body><h1>Authentication Successful!</h1><p>You can close this window and return to the terminal.</p></body></html>`)
+		close(s.done)
+	})
+}
+
 // Code returns the authorization code received
 func (s *CallbackServer) Code() string {
 	return s.code
 }
 
+// State returns the state received with the authorization code
+func (s *CallbackServer) State() string {
+	return s.state
+}
+
 // Close closes the callback server
 func (s *CallbackServer) Close() error {
-	return s.listener.Close()
+	var firstErr error
+	for _, listener := range s.listeners {
+		if err := listener.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// ParseCallbackURL extracts the authorization code from a pasted OAuth redirect URL
+func ParseCallbackURL(rawURL, expectedState string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", fmt.Errorf("invalid redirect URL: %w", err)
+	}
+
+	query := u.Query()
+	if errCode := query.Get("error"); errCode != "" {
+		return "", fmt.Errorf("OAuth error: %s", errCode)
+	}
+
+	if expectedState != "" && query.Get("state") != expectedState {
+		return "", fmt.Errorf("state mismatch")
+	}
+
+	code := query.Get("code")
+	if code == "" {
+		return "", fmt.Errorf("no authorization code received")
+	}
+	return code, nil
 }
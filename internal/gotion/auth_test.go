@@ -0,0 +1,141 @@
+package gotion
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestCallbackServer(t *testing.T) *CallbackServer {
+	t.Helper()
+
+	server, err := NewCallbackServer(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = server.Close() })
+	return server
+}
+
+func TestCallbackHandler(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		wantErr  bool
+		wantCode string
+	}{
+		{name: "matching state", query: "code=abc&state=expected", wantCode: "abc"},
+		{name: "mismatched state", query: "code=abc&state=forged", wantErr: true},
+		{name: "missing state", query: "code=abc", wantErr: true},
+		{name: "missing code", query: "state=expected", wantErr: true},
+		{name: "oauth error", query: "error=access_denied&state=expected", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestCallbackServer(t)
+
+			req := httptest.NewRequest(http.MethodGet, "/callback?"+tt.query, nil)
+			rec := httptest.NewRecorder()
+			server.handler("expected").ServeHTTP(rec, req)
+
+			select {
+			case <-server.done:
+			default:
+				t.Fatal("callback did not finish the flow")
+			}
+
+			if gotErr := server.err != nil; gotErr != tt.wantErr {
+				t.Errorf("err = %v, wantErr %v", server.err, tt.wantErr)
+			}
+			if server.Code() != tt.wantCode {
+				t.Errorf("Code() = %q, want %q", server.Code(), tt.wantCode)
+			}
+			if tt.wantErr && server.State() != "" {
+				t.Errorf("State() = %q after a failed callback, want empty", server.State())
+			}
+		})
+	}
+}
+
+func TestCallbackServerRejectsMismatchedState(t *testing.T) {
+	server := newTestCallbackServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	errc := make(chan error, 1)
+	go func() { errc <- server.Start(ctx, "expected") }()
+
+	resp, err := http.Get(fmt.Sprintf("http://127.0.0.1:%d/callback?code=abc&state=forged", server.Port()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if err := <-errc; err == nil || err.Error() != "state mismatch" {
+		t.Errorf("Start error = %v, want state mismatch", err)
+	}
+	if server.Code() != "" {
+		t.Errorf("Code() = %q, want no code after a state mismatch", server.Code())
+	}
+}
+
+func TestParseCallbackURL(t *testing.T) {
+	tests := []struct {
+		name    string
+		url     string
+		want    string
+		wantErr bool
+	}{
+		{name: "matching state", url: "http://localhost:8080/callback?code=abc&state=expected", want: "abc"},
+		{name: "mismatched state", url: "http://localhost:8080/callback?code=abc&state=forged", wantErr: true},
+		{name: "oauth error", url: "http://localhost:8080/callback?error=access_denied", wantErr: true},
+		{name: "missing code", url: "http://localhost:8080/callback?state=expected", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCallbackURL(tt.url, "expected")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCallbackURL error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCallbackURL = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCallbackServerListensOnBothLoopbacks(t *testing.T) {
+	for _, host := range []string{"127.0.0.1", "[::1]"} {
+		t.Run(host, func(t *testing.T) {
+			server := newTestCallbackServer(t)
+			if host == "[::1]" && len(server.listeners) < 2 {
+				t.Skip("IPv6 loopback is not available")
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			errc := make(chan error, 1)
+			go func() { errc <- server.Start(ctx, "expected") }()
+
+			resp, err := http.Get(fmt.Sprintf("http://%s:%d/callback?code=abc&state=expected", host, server.Port()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+
+			if err := <-errc; err != nil {
+				t.Fatalf("Start: %v", err)
+			}
+			if server.Code() != "abc" {
+				t.Errorf("Code() = %q, want %q", server.Code(), "abc")
+			}
+		})
+	}
+}
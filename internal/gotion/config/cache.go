@@ -0,0 +1,15 @@
+package config
+
+import "path/filepath"
+
+// CacheDirName is the name of the directory holding cached Notion responses
+const CacheDirName = "cache"
+
+// BlockCacheDir returns the directory of the on-disk page block cache
+func BlockCacheDir() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, CacheDirName, "blocks"), nil
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// CheckpointFileName is the name of the file holding database sync checkpoints
+const CheckpointFileName = "checkpoints.json"
+
+// LoadCheckpoint returns the stored last_edited_time checkpoint of a database,
+// or "" if none has been saved
+func LoadCheckpoint(databaseID string) (string, error) {
+	checkpoints, err := loadCheckpoints()
+	if err != nil {
+		return "", err
+	}
+	return checkpoints[databaseID], nil
+}
+
+// SaveCheckpoint stores the last_edited_time checkpoint of a database
+func SaveCheckpoint(databaseID, timestamp string) error {
+	checkpoints, err := loadCheckpoints()
+	if err != nil {
+		return err
+	}
+	checkpoints[databaseID] = timestamp
+
+	if err := EnsureConfigDir(); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	path, err := checkpointPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(checkpoints, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal checkpoints: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write checkpoint file: %w", err)
+	}
+	return nil
+}
+
+func loadCheckpoints() (map[string]string, error) {
+	path, err := checkpointPath()
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints := make(map[string]string)
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return checkpoints, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint file: %w", err)
+	}
+
+	if err := json.Unmarshal(data, &checkpoints); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal checkpoint file: %w", err)
+	}
+	return checkpoints, nil
+}
+
+func checkpointPath() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, CheckpointFileName), nil
+}
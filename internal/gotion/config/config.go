@@ -2,6 +2,7 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -21,6 +22,11 @@ const (
 
 	// TokenFileName is the name of the token file
 	TokenFileName = "token.json"
+	// EncryptedTokenFileName is the name of the encrypted token file
+	EncryptedTokenFileName = "token.json.enc"
+
+	// TokenPassphraseEnv is the environment variable holding the token file passphrase
+	TokenPassphraseEnv = "GOTION_TOKEN_PASSPHRASE"
 )
 
 // Config holds the application configuration
@@ -29,6 +35,59 @@ type Config struct {
 	ClientID     string  `mapstructure:"api_client_id"`
 	ClientSecret string  `mapstructure:"api_client_secret"`
 	Backend      Backend `mapstructure:"backend"`
+
+	// NotionVersion overrides the Notion-Version header sent by the API backend
+	NotionVersion string `mapstructure:"notion_version"`
+
+	// TokenBackend is the backend the token file's token was issued for;
+	// empty when the token comes from the environment or config file
+	TokenBackend Backend `mapstructure:"-"`
+
+	// Sources records where each config key's effective value came from
+	Sources map[string]string `mapstructure:"-"`
+}
+
+// Value sources reported in Config.Sources
+const (
+	SourceConfigFile = "config file"
+	SourceTokenFile  = "token file"
+	SourceDefault    = "default"
+)
+
+// RequestTimeout bounds each HTTP request of the API client, so a hung
+// connection fails even when the command has no --timeout
+const RequestTimeout = 30 * time.Second
+
+// ErrInvalidConfig is matched by errors about missing or invalid configuration
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// invalidConfigError is an error that matches ErrInvalidConfig
+type invalidConfigError struct {
+	msg string
+}
+
+func (e *invalidConfigError) Error() string { return e.msg }
+
+func (e *invalidConfigError) Unwrap() error { return ErrInvalidConfig }
+
+// configErrorf formats an error that matches ErrInvalidConfig
+func configErrorf(format string, args ...interface{}) error {
+	return &invalidConfigError{msg: fmt.Sprintf(format, args...)}
+}
+
+// envBinding maps a config key to its environment variable
+type envBinding struct {
+	Key string
+	Env string
+}
+
+// EnvBindings lists the config keys and the environment variables bound to them
+var EnvBindings = []envBinding{
+	{Key: "backend", Env: "GOTION_BACKEND"},
+	{Key: "api_client_id", Env: "GOTION_API_CLIENT_ID"},
+	{Key: "api_client_secret", Env: "GOTION_API_CLIENT_SECRET"},
+	{Key: "api_token", Env: "GOTION_API_TOKEN"},
+	{Key: "notion_version", Env: "GOTION_NOTION_VERSION"},
 }
 
 // Backend represents which Notion API backend to use
@@ -39,8 +98,34 @@ const (
 	BackendMCP Backend = "mcp"
 )
 
+// SourceFlag is the Config.Sources value for settings given by command-line flags
+const SourceFlag = "flag"
+
+// Validate returns an error if b is not a known backend
+func (b Backend) Validate() error {
+	switch b {
+	case BackendAPI, BackendMCP:
+		return nil
+	default:
+		return configErrorf("unknown backend: %s", b)
+	}
+}
+
+// backendOverride takes precedence over the configured backend when set
+var backendOverride Backend
+
+// SetBackend overrides the configured backend for this process
+func SetBackend(b Backend) error {
+	if err := b.Validate(); err != nil {
+		return err
+	}
+	backendOverride = b
+	return nil
+}
+
 // TokenData holds the OAuth token data
 type TokenData struct {
+	SchemaVersion int      `json:"schema_version,omitempty"`
 	Backend       Backend  `json:"backend"`
 	AccessToken   string   `json:"access_token"`
 	TokenType     string   `json:"token_type"`
@@ -62,10 +147,9 @@ func Load() (*Config, error) {
 	v.AutomaticEnv()
 
 	// Bind environment variables explicitly
-	_ = v.BindEnv("backend", "GOTION_BACKEND")
-	_ = v.BindEnv("api_client_id", "GOTION_API_CLIENT_ID")
-	_ = v.BindEnv("api_client_secret", "GOTION_API_CLIENT_SECRET")
-	_ = v.BindEnv("api_token", "GOTION_API_TOKEN")
+	for _, b := range EnvBindings {
+		_ = v.BindEnv(b.Key, b.Env)
+	}
 
 	// Load config file
 	configDir, err := GetConfigDir()
@@ -89,10 +173,29 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("failed to unmarshal config: %w", err)
 	}
 
+	// Record provenance of each key: env > config file > default
+	cfg.Sources = make(map[string]string)
+	for _, b := range EnvBindings {
+		switch {
+		case os.Getenv(b.Env) != "":
+			cfg.Sources[b.Key] = b.Env
+		case v.InConfig(b.Key):
+			cfg.Sources[b.Key] = SourceConfigFile
+		default:
+			cfg.Sources[b.Key] = SourceDefault
+		}
+	}
+
+	if backendOverride != "" {
+		cfg.Backend = backendOverride
+		cfg.Sources["backend"] = SourceFlag
+	}
+
 	// Also check NOTION_TOKEN as fallback for token
 	if cfg.Token == "" {
 		if token := os.Getenv("NOTION_TOKEN"); token != "" {
 			cfg.Token = token
+			cfg.Sources["api_token"] = "NOTION_TOKEN"
 		}
 	}
 
@@ -101,8 +204,22 @@ func Load() (*Config, error) {
 		tokenData, err := LoadToken()
 		if err == nil && tokenData.AccessToken != "" {
 			cfg.Token = tokenData.AccessToken
+			cfg.Sources["api_token"] = SourceTokenFile
+			// Tokens saved before the API flow recorded a backend are API tokens
+			cfg.TokenBackend = tokenData.Backend
+			if cfg.TokenBackend == "" {
+				cfg.TokenBackend = BackendAPI
+			}
+			// A profile created by auth --save-as may have no config file
+			if cfg.Backend == "" && tokenData.Backend != "" {
+				cfg.Backend = tokenData.Backend
+				cfg.Sources["backend"] = SourceTokenFile
+			}
 			if cfg.ClientID == "" {
 				cfg.ClientID = tokenData.ClientID
+				if cfg.ClientID != "" {
+					cfg.Sources["api_client_id"] = SourceTokenFile
+				}
 			}
 		}
 	}
@@ -110,18 +227,21 @@ func Load() (*Config, error) {
 	return &cfg, nil
 }
 
-// IsTokenExpired checks if the token is expired or about to expire (within 5 minutes)
-func (t *TokenData) IsTokenExpired() bool {
+// TokenRefreshSkew is how long before expiry a token is treated as expired,
+// so it is not used up in the middle of a request
+const TokenRefreshSkew = 5 * time.Minute
+
+// IsTokenExpired checks if the token is expired at now or expires within TokenRefreshSkew
+func (t *TokenData) IsTokenExpired(now time.Time) bool {
 	if t.ExpiresAt == 0 {
 		return false // No expiration info, assume valid
 	}
-	// Expired if within 5 minutes of expiration
-	return time.Now().Unix() > t.ExpiresAt-300
+	return now.Add(TokenRefreshSkew).Unix() > t.ExpiresAt
 }
 
-// NeedsRefresh checks if the token needs refresh
-func (t *TokenData) NeedsRefresh() bool {
-	return t.RefreshToken != "" && t.IsTokenExpired()
+// NeedsRefresh checks if the token needs refresh at now
+func (t *TokenData) NeedsRefresh(now time.Time) bool {
+	return t.RefreshToken != "" && t.IsTokenExpired(now)
 }
 
 // LoadOAuthConfig loads OAuth-specific configuration
@@ -163,13 +283,24 @@ func LoadOAuthConfig() (*Config, error) {
 	return &cfg, nil
 }
 
-// GetConfigDir returns the configuration directory path
+// GetConfigDir returns the configuration directory path.
+// For a named profile, this is the profile's directory under profiles/.
 func GetConfigDir() (string, error) {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(homeDir, ".config", "gotion"), nil
+
+	configDir := filepath.Join(homeDir, ".config", "gotion")
+
+	if profile := Profile(); profile != "" {
+		if err := validateProfile(profile); err != nil {
+			return "", err
+		}
+		configDir = filepath.Join(configDir, profilesDirName, profile)
+	}
+
+	return configDir, nil
 }
 
 // EnsureConfigDir ensures the configuration directory exists
@@ -181,8 +312,37 @@ func EnsureConfigDir() error {
 	return os.MkdirAll(configDir, 0700)
 }
 
-// SaveToken saves the OAuth token to the token file
+// GetTokenPath returns the path of the token file in use.
+// When GOTION_TOKEN_PASSPHRASE is set, the encrypted token file is used.
+func GetTokenPath() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	if os.Getenv(TokenPassphraseEnv) != "" {
+		return filepath.Join(configDir, EncryptedTokenFileName), nil
+	}
+	return filepath.Join(configDir, TokenFileName), nil
+}
+
+// SaveToken saves the OAuth token to the token store.
+// With the file store and GOTION_TOKEN_PASSPHRASE set, the token is encrypted
+// and any plaintext token file is removed.
 func SaveToken(token *TokenData) error {
+	token.SchemaVersion = TokenSchemaVersion
+
+	if TokenStore() == TokenStoreKeychain {
+		data, err := json.Marshal(token)
+		if err != nil {
+			return fmt.Errorf("failed to marshal token: %w", err)
+		}
+		if err := systemKeychain.Set(keychainService, keychainAccount(), string(data)); err != nil {
+			return fmt.Errorf("failed to save token to keychain: %w", err)
+		}
+		return nil
+	}
+
 	if err := EnsureConfigDir(); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
@@ -199,6 +359,22 @@ func SaveToken(token *TokenData) error {
 		return fmt.Errorf("failed to marshal token: %w", err)
 	}
 
+	if passphrase := os.Getenv(TokenPassphraseEnv); passphrase != "" {
+		encrypted, err := encryptToken(data, passphrase)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt token: %w", err)
+		}
+
+		if err := os.WriteFile(filepath.Join(configDir, EncryptedTokenFileName), encrypted, 0600); err != nil {
+			return fmt.Errorf("failed to write token file: %w", err)
+		}
+
+		if err := os.Remove(tokenPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove plaintext token file: %w", err)
+		}
+		return nil
+	}
+
 	if err := os.WriteFile(tokenPath, data, 0600); err != nil {
 		return fmt.Errorf("failed to write token file: %w", err)
 	}
@@ -206,39 +382,83 @@ func SaveToken(token *TokenData) error {
 	return nil
 }
 
-// LoadToken loads the OAuth token from the token file
+// LoadToken loads the OAuth token from the token store.
+// With the file store and GOTION_TOKEN_PASSPHRASE set, the encrypted token
+// file is read, falling back to the plaintext file if no encrypted file exists yet.
 func LoadToken() (*TokenData, error) {
-	configDir, err := GetConfigDir()
-	if err != nil {
-		return nil, err
-	}
+	token, _, err := loadToken()
+	return token, err
+}
 
-	tokenPath := filepath.Join(configDir, TokenFileName)
+// loadToken loads the stored token, upgraded to the current schema, and
+// returns the schema version it was stored in
+func loadToken() (*TokenData, int, error) {
+	if TokenStore() == TokenStoreKeychain {
+		secret, err := systemKeychain.Get(keychainService, keychainAccount())
+		if err != nil {
+			return nil, 0, err
+		}
+		return decodeToken([]byte(secret))
+	}
 
-	data, err := os.ReadFile(tokenPath)
+	configDir, err := GetConfigDir()
 	if err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	var token TokenData
-	if err := json.Unmarshal(data, &token); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal token: %w", err)
+	tokenPath := filepath.Join(configDir, TokenFileName)
+	encPath := filepath.Join(configDir, EncryptedTokenFileName)
+
+	var data []byte
+	if passphrase := os.Getenv(TokenPassphraseEnv); passphrase != "" {
+		encrypted, err := os.ReadFile(encPath)
+		switch {
+		case err == nil:
+			data, err = decryptToken(encrypted, passphrase)
+			if err != nil {
+				return nil, 0, err
+			}
+		case os.IsNotExist(err):
+			data, err = os.ReadFile(tokenPath)
+			if err != nil {
+				return nil, 0, err
+			}
+		default:
+			return nil, 0, err
+		}
+	} else {
+		data, err = os.ReadFile(tokenPath)
+		if err != nil {
+			if _, encErr := os.Stat(encPath); os.IsNotExist(err) && encErr == nil {
+				return nil, 0, configErrorf("token file is encrypted, set %s to decrypt it", TokenPassphraseEnv)
+			}
+			return nil, 0, err
+		}
 	}
 
-	return &token, nil
+	return decodeToken(data)
 }
 
-// DeleteToken deletes the OAuth token file
+// DeleteToken deletes the stored OAuth token.
+// With the file store, both plaintext and encrypted token files are removed.
 func DeleteToken() error {
+	if TokenStore() == TokenStoreKeychain {
+		if err := systemKeychain.Delete(keychainService, keychainAccount()); err != nil && err != errKeychainNotFound {
+			return fmt.Errorf("failed to delete token from keychain: %w", err)
+		}
+		return nil
+	}
+
 	configDir, err := GetConfigDir()
 	if err != nil {
 		return err
 	}
 
-	tokenPath := filepath.Join(configDir, TokenFileName)
-
-	if err := os.Remove(tokenPath); err != nil && !os.IsNotExist(err) {
-		return fmt.Errorf("failed to delete token file: %w", err)
+	for _, name := range []string{TokenFileName, EncryptedTokenFileName} {
+		tokenPath := filepath.Join(configDir, name)
+		if err := os.Remove(tokenPath); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to delete token file: %w", err)
+		}
 	}
 
 	return nil
@@ -247,7 +467,17 @@ func DeleteToken() error {
 // Validate checks if the configuration is valid
 func (c *Config) Validate() error {
 	if c.Token == "" {
-		return fmt.Errorf("token is required. Run 'gotion auth' or set GOTION_API_TOKEN/NOTION_TOKEN environment variable")
+		return configErrorf("token is required. Run 'gotion auth' or set GOTION_API_TOKEN/NOTION_TOKEN environment variable")
+	}
+	if c.NotionVersion != "" {
+		if _, err := time.Parse("2006-01-02", c.NotionVersion); err != nil {
+			return configErrorf("invalid notion_version %q: must be a date in YYYY-MM-DD format", c.NotionVersion)
+		}
+	}
+	// A token only works with the backend that issued it
+	if c.TokenBackend != "" && c.Backend != "" && c.Backend != c.TokenBackend {
+		return configErrorf("the saved token is for the %s backend but backend is %s (from %s). Run 'gotion auth' with backend %s, or set backend = %s",
+			c.TokenBackend, c.Backend, c.Sources["backend"], c.Backend, c.TokenBackend)
 	}
 	return nil
 }
@@ -255,10 +485,101 @@ func (c *Config) Validate() error {
 // ValidateOAuth checks if the OAuth configuration is valid
 func (c *Config) ValidateOAuth() error {
 	if c.ClientID == "" {
-		return fmt.Errorf("api_client_id is required. Set GOTION_API_CLIENT_ID environment variable or configure in ~/.config/gotion/config.toml")
+		return configErrorf("api_client_id is required. Set GOTION_API_CLIENT_ID environment variable or configure in ~/.config/gotion/config.toml")
 	}
 	if c.ClientSecret == "" {
-		return fmt.Errorf("api_client_secret is required. Set GOTION_API_CLIENT_SECRET environment variable or configure in ~/.config/gotion/config.toml")
+		return configErrorf("api_client_secret is required. Set GOTION_API_CLIENT_SECRET environment variable or configure in ~/.config/gotion/config.toml")
+	}
+	return nil
+}
+
+// keyAliases maps short key names accepted by SetValue and GetValue to config keys
+var keyAliases = map[string]string{
+	"client_id":     "api_client_id",
+	"client_secret": "api_client_secret",
+	"token":         "api_token",
+}
+
+// ResolveKey returns the config key for a key name or alias
+func ResolveKey(key string) (string, error) {
+	if alias, ok := keyAliases[key]; ok {
+		key = alias
 	}
+	for _, b := range EnvBindings {
+		if b.Key == key {
+			return key, nil
+		}
+	}
+	return "", fmt.Errorf("unknown config key: %s", key)
+}
+
+// Get returns the effective value of a config key
+func (c *Config) Get(key string) (string, error) {
+	key, err := ResolveKey(key)
+	if err != nil {
+		return "", err
+	}
+
+	switch key {
+	case "backend":
+		return string(c.Backend), nil
+	case "api_client_id":
+		return c.ClientID, nil
+	case "api_client_secret":
+		return c.ClientSecret, nil
+	case "api_token":
+		return c.Token, nil
+	case "notion_version":
+		return c.NotionVersion, nil
+	}
+	return "", fmt.Errorf("unknown config key: %s", key)
+}
+
+// SetValue validates a value and writes it to the config file
+func SetValue(key, value string) error {
+	key, err := ResolveKey(key)
+	if err != nil {
+		return err
+	}
+
+	switch key {
+	case "backend":
+		if Backend(value) != BackendAPI && Backend(value) != BackendMCP {
+			return fmt.Errorf("invalid backend: %s (supported: api, mcp)", value)
+		}
+	case "notion_version":
+		if _, err := time.Parse("2006-01-02", value); err != nil {
+			return fmt.Errorf("invalid notion_version %q: must be a date in YYYY-MM-DD format", value)
+		}
+	}
+
+	if err := EnsureConfigDir(); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return err
+	}
+	configPath := filepath.Join(configDir, ConfigFileName+"."+ConfigFileType)
+
+	// Read the config file only, so environment values are not written back
+	v := viper.New()
+	v.SetConfigFile(configPath)
+	if err := v.ReadInConfig(); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	v.Set(key, value)
+
+	if err := v.WriteConfigAs(configPath); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+
+	// The config file may hold secrets
+	if err := os.Chmod(configPath, 0600); err != nil {
+		return fmt.Errorf("failed to set config file permissions: %w", err)
+	}
+
 	return nil
 }
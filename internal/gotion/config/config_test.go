@@ -0,0 +1,154 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+// useTempConfigDir points the config directory at a fresh temporary home
+// and clears the environment variables that would override stored settings
+func useTempConfigDir(t *testing.T) {
+	t.Helper()
+
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv(ProfileEnv, "")
+	t.Setenv(TokenStoreEnv, "")
+	t.Setenv(TokenPassphraseEnv, "")
+	t.Setenv("NOTION_TOKEN", "")
+	for _, b := range EnvBindings {
+		t.Setenv(b.Env, "")
+	}
+}
+
+func TestSaveLoadTokenRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		token TokenData
+	}{
+		{
+			name: "api oauth",
+			token: TokenData{
+				Backend:       BackendAPI,
+				AccessToken:   "secret_api",
+				TokenType:     "bearer",
+				BotID:         "bot",
+				WorkspaceID:   "ws",
+				WorkspaceName: "Workspace",
+				RefreshToken:  "refresh_api",
+				ExpiresAt:     1700000000,
+			},
+		},
+		{
+			name: "mcp",
+			token: TokenData{
+				Backend:      BackendMCP,
+				AccessToken:  "secret_mcp",
+				TokenType:    "bearer",
+				ClientID:     "client",
+				RefreshToken: "refresh_mcp",
+				ExpiresAt:    1700000000,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useTempConfigDir(t)
+
+			saved := tt.token
+			if err := SaveToken(&saved); err != nil {
+				t.Fatalf("SaveToken: %v", err)
+			}
+
+			loaded, err := LoadToken()
+			if err != nil {
+				t.Fatalf("LoadToken: %v", err)
+			}
+			want := tt.token
+			want.SchemaVersion = TokenSchemaVersion
+			if *loaded != want {
+				t.Errorf("LoadToken = %+v, want %+v", *loaded, want)
+			}
+
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.TokenBackend != tt.token.Backend {
+				t.Errorf("TokenBackend = %q, want %q", cfg.TokenBackend, tt.token.Backend)
+			}
+		})
+	}
+}
+
+func TestLoadTokenWithoutBackendIsAPI(t *testing.T) {
+	useTempConfigDir(t)
+
+	if err := SaveToken(&TokenData{AccessToken: "secret", TokenType: "bearer"}); err != nil {
+		t.Fatalf("SaveToken: %v", err)
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.TokenBackend != BackendAPI {
+		t.Errorf("TokenBackend = %q, want %q", cfg.TokenBackend, BackendAPI)
+	}
+
+	cfg.Backend = BackendMCP
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate accepted an API token with the MCP backend")
+	}
+}
+
+func TestIsTokenExpired(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		expiresAt int64
+		want      bool
+	}{
+		{name: "zero", expiresAt: 0, want: false},
+		{name: "expired", expiresAt: now.Add(-time.Hour).Unix(), want: true},
+		{name: "expires now", expiresAt: now.Unix(), want: true},
+		{name: "within skew", expiresAt: now.Add(TokenRefreshSkew - time.Second).Unix(), want: true},
+		{name: "just past skew", expiresAt: now.Add(TokenRefreshSkew + time.Second).Unix(), want: false},
+		{name: "far future", expiresAt: now.Add(30 * 24 * time.Hour).Unix(), want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := &TokenData{ExpiresAt: tt.expiresAt}
+			if got := token.IsTokenExpired(now); got != tt.want {
+				t.Errorf("IsTokenExpired = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNeedsRefresh(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	expired := now.Add(-time.Minute).Unix()
+
+	tests := []struct {
+		name  string
+		token TokenData
+		want  bool
+	}{
+		{name: "expired with refresh token", token: TokenData{RefreshToken: "r", ExpiresAt: expired}, want: true},
+		{name: "near expiry with refresh token", token: TokenData{RefreshToken: "r", ExpiresAt: now.Add(time.Minute).Unix()}, want: true},
+		{name: "expired without refresh token", token: TokenData{ExpiresAt: expired}, want: false},
+		{name: "far future", token: TokenData{RefreshToken: "r", ExpiresAt: now.Add(time.Hour).Unix()}, want: false},
+		{name: "zero expiry", token: TokenData{RefreshToken: "r"}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.token.NeedsRefresh(now); got != tt.want {
+				t.Errorf("NeedsRefresh = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"fmt"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// scrypt parameters for deriving the token encryption key
+	scryptN      = 1 << 15
+	scryptR      = 8
+	scryptP      = 1
+	scryptKeyLen = 32
+
+	saltSize = 16
+)
+
+// encryptToken encrypts data with AES-GCM using a key derived from passphrase.
+// The output layout is salt || nonce || ciphertext.
+func encryptToken(data []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	gcm, err := newTokenCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	out := make([]byte, 0, len(salt)+len(nonce)+len(data)+gcm.Overhead())
+	out = append(out, salt...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, data, nil), nil
+}
+
+// decryptToken decrypts data produced by encryptToken
+func decryptToken(data []byte, passphrase string) ([]byte, error) {
+	if len(data) < saltSize {
+		return nil, fmt.Errorf("encrypted token file is too short")
+	}
+	salt := data[:saltSize]
+
+	gcm, err := newTokenCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	rest := data[saltSize:]
+	if len(rest) < gcm.NonceSize() {
+		return nil, fmt.Errorf("encrypted token file is too short")
+	}
+	nonce, ciphertext := rest[:gcm.NonceSize()], rest[gcm.NonceSize():]
+
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt token file (wrong passphrase?)")
+	}
+	return plaintext, nil
+}
+
+func newTokenCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, scryptKeyLen)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+const (
+	// TokenStoreEnv is the environment variable selecting the token store
+	TokenStoreEnv = "GOTION_TOKEN_STORE"
+
+	// TokenStoreFile stores tokens in the token file (default)
+	TokenStoreFile = "file"
+	// TokenStoreKeychain stores tokens in the OS keychain
+	TokenStoreKeychain = "keychain"
+
+	keychainService = "gotion"
+)
+
+// errKeychainNotFound is returned when no secret is stored in the keychain
+var errKeychainNotFound = errors.New("token not found in keychain")
+
+// keychain is an OS-specific secret store
+type keychain interface {
+	Set(service, account, secret string) error
+	Get(service, account string) (string, error)
+	Delete(service, account string) error
+}
+
+// keychainAccount returns the keychain account name for the active profile
+func keychainAccount() string {
+	if profile := Profile(); profile != "" {
+		return "token:" + profile
+	}
+	return "token"
+}
+
+// tokenStoreOverride takes precedence over GOTION_TOKEN_STORE when set
+var tokenStoreOverride string
+
+// UseTokenStore overrides the token store selected by GOTION_TOKEN_STORE
+func UseTokenStore(store string) {
+	tokenStoreOverride = store
+}
+
+// TokenStore returns the token store in use: TokenStoreFile or TokenStoreKeychain
+func TokenStore() string {
+	if tokenStoreOverride != "" {
+		return tokenStoreOverride
+	}
+	if os.Getenv(TokenStoreEnv) == TokenStoreKeychain {
+		return TokenStoreKeychain
+	}
+	return TokenStoreFile
+}
+
+// TokenLocation returns a human readable location of the token store in use
+func TokenLocation() (string, error) {
+	if TokenStore() == TokenStoreKeychain {
+		return "keychain (service: " + keychainService + ", account: " + keychainAccount() + ")", nil
+	}
+	return GetTokenPath()
+}
+
+// TokenExists reports whether a stored token exists in the token store in use
+func TokenExists() bool {
+	if TokenStore() == TokenStoreKeychain {
+		_, err := systemKeychain.Get(keychainService, keychainAccount())
+		return err == nil
+	}
+
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return false
+	}
+	for _, name := range []string{TokenFileName, EncryptedTokenFileName} {
+		if _, err := os.Stat(filepath.Join(configDir, name)); err == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+const securityPath = "/usr/bin/security"
+
+// macKeychain stores secrets in the macOS Keychain using the security tool
+type macKeychain struct{}
+
+var systemKeychain keychain = macKeychain{}
+
+func (macKeychain) Set(service, account, secret string) error {
+	// Encode the secret so it survives the interactive command line unquoted
+	encoded := base64.StdEncoding.EncodeToString([]byte(secret))
+
+	cmd := exec.Command(securityPath, "-i")
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to run security: %w", err)
+	}
+
+	// Pass the secret via stdin so it does not appear in the process list
+	if _, err := io.WriteString(stdin, fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", service, account, encoded)); err != nil {
+		return err
+	}
+	if err := stdin.Close(); err != nil {
+		return err
+	}
+
+	return cmd.Wait()
+}
+
+func (macKeychain) Get(service, account string) (string, error) {
+	out, err := exec.Command(securityPath, "find-generic-password", "-s", service, "-a", account, "-w").CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(out), "could not be found") {
+			return "", errKeychainNotFound
+		}
+		return "", fmt.Errorf("failed to read keychain: %s", strings.TrimSpace(string(out)))
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(out)))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode keychain secret: %w", err)
+	}
+	return string(decoded), nil
+}
+
+func (macKeychain) Delete(service, account string) error {
+	out, err := exec.Command(securityPath, "delete-generic-password", "-s", service, "-a", account).CombinedOutput()
+	if err != nil {
+		if strings.Contains(string(out), "could not be found") {
+			return errKeychainNotFound
+		}
+		return fmt.Errorf("failed to delete keychain entry: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
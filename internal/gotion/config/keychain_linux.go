@@ -0,0 +1,54 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// secretServiceKeychain stores secrets via the Secret Service API using secret-tool
+type secretServiceKeychain struct{}
+
+var systemKeychain keychain = secretServiceKeychain{}
+
+func (secretServiceKeychain) Set(service, account, secret string) error {
+	// secret-tool reads the secret from stdin
+	cmd := exec.Command("secret-tool", "store", "--label="+service, "service", service, "account", account)
+	cmd.Stdin = strings.NewReader(secret)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to write keychain: %s", keychainErrMessage(out, err))
+	}
+	return nil
+}
+
+func (secretServiceKeychain) Get(service, account string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "lookup", "service", service, "account", account)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		// secret-tool exits 1 with no output when nothing matches
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && stderr.Len() == 0 {
+			return "", errKeychainNotFound
+		}
+		return "", fmt.Errorf("failed to read keychain: %s", keychainErrMessage(stderr.Bytes(), err))
+	}
+	return stdout.String(), nil
+}
+
+func (secretServiceKeychain) Delete(service, account string) error {
+	if out, err := exec.Command("secret-tool", "clear", "service", service, "account", account).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to delete keychain entry: %s", keychainErrMessage(out, err))
+	}
+	return nil
+}
+
+func keychainErrMessage(out []byte, err error) string {
+	if msg := strings.TrimSpace(string(out)); msg != "" {
+		return msg
+	}
+	return err.Error()
+}
@@ -0,0 +1,22 @@
+//go:build !darwin && !linux && !windows
+
+package config
+
+import "fmt"
+
+// unsupportedKeychain is used on platforms without keychain support
+type unsupportedKeychain struct{}
+
+var systemKeychain keychain = unsupportedKeychain{}
+
+func (unsupportedKeychain) Set(service, account, secret string) error {
+	return fmt.Errorf("keychain is not supported on this platform")
+}
+
+func (unsupportedKeychain) Get(service, account string) (string, error) {
+	return "", fmt.Errorf("keychain is not supported on this platform")
+}
+
+func (unsupportedKeychain) Delete(service, account string) error {
+	return fmt.Errorf("keychain is not supported on this platform")
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// TokenSchemaVersion is the current version of the stored token format.
+// Version 0 tokens have no schema_version and may name the backend auth_type.
+const TokenSchemaVersion = 1
+
+// decodeToken decodes stored token data, upgrading it to the current schema,
+// and returns the schema version it was stored in
+func decodeToken(data []byte) (*TokenData, int, error) {
+	var token TokenData
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal token: %w", err)
+	}
+
+	version := token.SchemaVersion
+	if err := migrateToken(data, &token); err != nil {
+		return nil, 0, err
+	}
+	return &token, version, nil
+}
+
+// migrateToken upgrades token, decoded from data, to TokenSchemaVersion
+func migrateToken(data []byte, token *TokenData) error {
+	if token.SchemaVersion > TokenSchemaVersion {
+		return fmt.Errorf("token schema version %d is newer than supported (%d); upgrade gotion", token.SchemaVersion, TokenSchemaVersion)
+	}
+
+	// v0 → v1: the backend was stored as auth_type
+	if token.SchemaVersion < 1 {
+		if token.Backend == "" {
+			var legacy struct {
+				AuthType Backend `json:"auth_type"`
+			}
+			if err := json.Unmarshal(data, &legacy); err != nil {
+				return fmt.Errorf("failed to unmarshal token: %w", err)
+			}
+			// Unknown values are dropped so the configured backend applies
+			if legacy.AuthType.Validate() == nil {
+				token.Backend = legacy.AuthType
+			}
+		}
+		token.SchemaVersion = 1
+	}
+
+	return nil
+}
+
+// MigrateToken rewrites the stored token in the current schema version and
+// returns the version it was stored in. A current token is left untouched.
+func MigrateToken() (int, error) {
+	token, version, err := loadToken()
+	if err != nil {
+		return 0, err
+	}
+	if version == TokenSchemaVersion {
+		return version, nil
+	}
+
+	if err := SaveToken(token); err != nil {
+		return 0, err
+	}
+	return version, nil
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+)
+
+func TestDecodeToken(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantBackend Backend
+		wantVersion int
+		wantErr     bool
+	}{
+		{name: "v0 auth_type mcp", data: `{"auth_type":"mcp","access_token":"t"}`, wantBackend: BackendMCP},
+		{name: "v0 auth_type api", data: `{"auth_type":"api","access_token":"t"}`, wantBackend: BackendAPI},
+		{name: "v0 backend wins over auth_type", data: `{"backend":"api","auth_type":"mcp","access_token":"t"}`, wantBackend: BackendAPI},
+		{name: "v0 unknown auth_type", data: `{"auth_type":"oauth","access_token":"t"}`, wantBackend: ""},
+		{name: "v0 without backend", data: `{"access_token":"t"}`, wantBackend: ""},
+		{name: "v1", data: `{"schema_version":1,"backend":"mcp","access_token":"t"}`, wantBackend: BackendMCP, wantVersion: 1},
+		{name: "newer version", data: `{"schema_version":99,"backend":"mcp","access_token":"t"}`, wantErr: true},
+		{name: "malformed", data: `{`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, version, err := decodeToken([]byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeToken error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if token.Backend != tt.wantBackend {
+				t.Errorf("Backend = %q, want %q", token.Backend, tt.wantBackend)
+			}
+			if version != tt.wantVersion {
+				t.Errorf("stored version = %d, want %d", version, tt.wantVersion)
+			}
+			if token.SchemaVersion != TokenSchemaVersion {
+				t.Errorf("SchemaVersion = %d, want %d", token.SchemaVersion, TokenSchemaVersion)
+			}
+			if token.AccessToken != "t" {
+				t.Errorf("AccessToken = %q, want %q", token.AccessToken, "t")
+			}
+		})
+	}
+}
+
+func TestMigrateTokenRewritesV0File(t *testing.T) {
+	useTempConfigDir(t)
+
+	if err := EnsureConfigDir(); err != nil {
+		t.Fatal(err)
+	}
+	tokenPath, err := GetTokenPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v0 := `{"auth_type":"mcp","access_token":"t","client_id":"c","refresh_token":"r","expires_at":1700000000}`
+	if err := os.WriteFile(tokenPath, []byte(v0), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	version, err := MigrateToken()
+	if err != nil {
+		t.Fatalf("MigrateToken: %v", err)
+	}
+	if version != 0 {
+		t.Errorf("MigrateToken = %d, want 0", version)
+	}
+
+	data, err := os.ReadFile(tokenPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stored map[string]interface{}
+	if err := json.Unmarshal(data, &stored); err != nil {
+		t.Fatal(err)
+	}
+	if stored["schema_version"] != float64(TokenSchemaVersion) || stored["backend"] != "mcp" {
+		t.Errorf("rewritten token = %s, want schema_version %d and backend mcp", data, TokenSchemaVersion)
+	}
+	if _, ok := stored["auth_type"]; ok {
+		t.Errorf("rewritten token still has auth_type: %s", data)
+	}
+
+	// A current token is left as it is
+	version, err = MigrateToken()
+	if err != nil {
+		t.Fatalf("MigrateToken: %v", err)
+	}
+	if version != TokenSchemaVersion {
+		t.Errorf("second MigrateToken = %d, want %d", version, TokenSchemaVersion)
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// ProfileEnv is the environment variable selecting the active profile
+	ProfileEnv = "GOTION_PROFILE"
+
+	// profilesDirName is the directory under the config directory holding named profiles
+	profilesDirName = "profiles"
+)
+
+// profileOverride takes precedence over GOTION_PROFILE when set
+var profileOverride string
+
+// SetProfile sets the active profile, overriding GOTION_PROFILE
+func SetProfile(name string) error {
+	if err := validateProfile(name); err != nil {
+		return err
+	}
+	profileOverride = name
+	return nil
+}
+
+// Profile returns the active profile name, or "" for the default profile
+func Profile() string {
+	if profileOverride != "" {
+		return profileOverride
+	}
+	return os.Getenv(ProfileEnv)
+}
+
+func validateProfile(name string) error {
+	if name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+		return fmt.Errorf("invalid profile name: %q", name)
+	}
+	return nil
+}
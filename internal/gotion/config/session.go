@@ -0,0 +1,66 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MCPSessionFileName is the name of the file holding the MCP session ID reused across commands
+const MCPSessionFileName = "mcp_session"
+
+// LoadMCPSession returns the saved MCP session ID, or "" if none has been saved
+func LoadMCPSession() (string, error) {
+	path, err := mcpSessionPath()
+	if err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read MCP session file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// SaveMCPSession saves the MCP session ID for later commands
+func SaveMCPSession(sessionID string) error {
+	if err := EnsureConfigDir(); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+
+	path, err := mcpSessionPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, []byte(sessionID+"\n"), 0600); err != nil {
+		return fmt.Errorf("failed to write MCP session file: %w", err)
+	}
+	return nil
+}
+
+// DeleteMCPSession removes the saved MCP session ID, if any
+func DeleteMCPSession() error {
+	path, err := mcpSessionPath()
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete MCP session file: %w", err)
+	}
+	return nil
+}
+
+func mcpSessionPath() (string, error) {
+	configDir, err := GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, MCPSessionFileName), nil
+}
@@ -1,15 +1,23 @@
 package gotion
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
+
+	"golang.org/x/text/width"
 )
 
 // PageOutput is the intermediate structure for page formatting
 type PageOutput struct {
-	Title   string
-	URL     string
-	Content string
+	Title      string
+	URL        string
+	PublicURL  string
+	Properties map[string]string // Written to the frontmatter, sorted by name
+	Content    string
 }
 
 // SearchPageItem represents a single page in search results
@@ -30,8 +38,18 @@ func FormatPage(output *PageOutput) string {
 	var sb strings.Builder
 
 	sb.WriteString("---\n")
-	sb.WriteString(fmt.Sprintf("title: %q\n", output.Title))
+	sb.WriteString(fmt.Sprintf("title: %s\n", yamlString(output.Title)))
 	sb.WriteString(fmt.Sprintf("url: %s\n", output.URL))
+	if output.PublicURL != "" {
+		sb.WriteString(fmt.Sprintf("public_url: %s\n", output.PublicURL))
+	}
+	for _, name := range slices.Sorted(maps.Keys(output.Properties)) {
+		if name == "title" || name == "url" || name == "public_url" {
+			continue
+		}
+		// Property names may contain ':', '#' and other YAML indicators, so keys are quoted too
+		sb.WriteString(fmt.Sprintf("%s: %s\n", yamlString(name), yamlString(output.Properties[name])))
+	}
 	sb.WriteString("---\n\n")
 
 	if output.Content != "" {
@@ -41,6 +59,27 @@ func FormatPage(output *PageOutput) string {
 	return sb.String()
 }
 
+// yamlString quotes s as a YAML double-quoted scalar. JSON strings are valid
+// YAML, unlike Go's %q, whose escapes differ.
+func yamlString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s) // Encoding a string cannot fail
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// FormatProperties formats properties as "name: value" lines sorted by name
+func FormatProperties(properties map[string]string) string {
+	var sb strings.Builder
+
+	for _, name := range slices.Sorted(maps.Keys(properties)) {
+		sb.WriteString(fmt.Sprintf("%s: %s\n", name, properties[name]))
+	}
+
+	return sb.String()
+}
+
 // FormatSearch formats a SearchOutput as Markdown
 func FormatSearch(output *SearchOutput) string {
 	var sb strings.Builder
@@ -55,3 +94,144 @@ func FormatSearch(output *SearchOutput) string {
 
 	return sb.String()
 }
+
+// FormatMarkdownTable formats rows as a Markdown pipe table
+func FormatMarkdownTable(headers []string, rows [][]string) string {
+	return formatMarkdownTable(headers, rows, false)
+}
+
+// FormatMarkdownTableColor formats rows as a Markdown pipe table for a terminal,
+// with a bold header and every other row dimmed
+func FormatMarkdownTableColor(headers []string, rows [][]string) string {
+	return formatMarkdownTable(headers, rows, true)
+}
+
+func formatMarkdownTable(headers []string, rows [][]string, color bool) string {
+	var sb strings.Builder
+
+	writeRow := func(cells []string, style string) {
+		if color && style != "" {
+			sb.WriteString(style)
+		}
+		sb.WriteString("|")
+		for _, cell := range cells {
+			sb.WriteString(" ")
+			sb.WriteString(escapeMarkdownCell(cell))
+			sb.WriteString(" |")
+		}
+		if color && style != "" {
+			sb.WriteString(ansiReset)
+		}
+		sb.WriteString("\n")
+	}
+
+	writeRow(headers, ansiBold)
+	sb.WriteString("|")
+	for range headers {
+		sb.WriteString(" --- |")
+	}
+	sb.WriteString("\n")
+	for i, row := range rows {
+		style := ""
+		if i%2 == 1 {
+			style = ansiDim
+		}
+		writeRow(row, style)
+	}
+
+	return sb.String()
+}
+
+// escapeMarkdownCell escapes pipes and flattens newlines so a value fits in one table cell
+func escapeMarkdownCell(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.ReplaceAll(s, "\r\n", " ")
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+// HistogramBucket is a single value and its count in a histogram
+type HistogramBucket struct {
+	Value string
+	Count int
+}
+
+// histogramBarWidth is the width of the longest bar in a text histogram
+const histogramBarWidth = 40
+
+// FormatHistogram formats histogram buckets as a text bar chart
+func FormatHistogram(buckets []HistogramBucket) string {
+	var sb strings.Builder
+
+	labelWidth, maxCount := 0, 0
+	for _, b := range buckets {
+		if w := DisplayWidth(b.Value); w > labelWidth {
+			labelWidth = w
+		}
+		if b.Count > maxCount {
+			maxCount = b.Count
+		}
+	}
+
+	for _, b := range buckets {
+		barLen := 0
+		if maxCount > 0 {
+			barLen = b.Count * histogramBarWidth / maxCount
+		}
+		if barLen == 0 && b.Count > 0 {
+			barLen = 1
+		}
+		padding := strings.Repeat(" ", labelWidth-DisplayWidth(b.Value))
+		sb.WriteString(fmt.Sprintf("%s%s  %s %d\n", b.Value, padding, strings.Repeat("█", barLen), b.Count))
+	}
+
+	return sb.String()
+}
+
+// DisplayWidth returns the number of terminal columns s occupies:
+// East Asian wide and fullwidth characters count as two columns
+func DisplayWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		switch width.LookupRune(r).Kind() {
+		case width.EastAsianWide, width.EastAsianFullwidth:
+			n += 2
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// FormatHistogramJSON formats histogram buckets as a JSON object, preserving bucket order
+func FormatHistogramJSON(buckets []HistogramBucket) (string, error) {
+	var sb strings.Builder
+
+	sb.WriteString("{")
+	for i, b := range buckets {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		key, err := json.Marshal(b.Value)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal value: %w", err)
+		}
+		sb.WriteString(fmt.Sprintf("\n  %s: %d", key, b.Count))
+	}
+	if len(buckets) > 0 {
+		sb.WriteString("\n")
+	}
+	sb.WriteString("}\n")
+
+	return sb.String(), nil
+}
+
+// CompactJSON removes insignificant whitespace from JSON output, keeping a trailing newline
+func CompactJSON(s string) (string, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, []byte(s)); err != nil {
+		return "", fmt.Errorf("failed to compact JSON: %w", err)
+	}
+	buf.WriteByte('\n')
+	return buf.String(), nil
+}
@@ -0,0 +1,136 @@
+package gotion
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"", 0},
+		{"abc", 3},
+		{"日本語", 6},
+		{"ﾆﾎﾝｺﾞ", 5}, // halfwidth katakana
+		{"ＡＢ", 4},    // fullwidth latin
+		{"Todo 完了", 9},
+		{"😀", 2},
+		{"Done ✅", 7},
+		{"café", 4},
+	}
+
+	for _, tt := range tests {
+		if got := DisplayWidth(tt.s); got != tt.want {
+			t.Errorf("DisplayWidth(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestFormatHistogramAlignsWideLabels(t *testing.T) {
+	buckets := []HistogramBucket{
+		{Value: "完了", Count: 4},
+		{Value: "Done", Count: 2},
+		{Value: "🚧 WIP", Count: 1},
+		{Value: "x", Count: 1},
+	}
+	output := FormatHistogram(buckets)
+
+	lines := strings.Split(strings.TrimSuffix(output, "\n"), "\n")
+	if len(lines) != len(buckets) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(lines), len(buckets), output)
+	}
+
+	// Every bar starts in the same terminal column: after the widest label and two spaces
+	want := DisplayWidth("🚧 WIP") + 2
+	for i, line := range lines {
+		rest, ok := strings.CutPrefix(line, buckets[i].Value)
+		if !ok {
+			t.Fatalf("line %q does not start with %q", line, buckets[i].Value)
+		}
+		padding := len(rest) - len(strings.TrimLeft(rest, " "))
+		if col := DisplayWidth(buckets[i].Value) + padding; col != want {
+			t.Errorf("line %q: bar starts at column %d, want %d\n%s", line, col, want, output)
+		}
+	}
+}
+
+func TestFormatMarkdownTableGolden(t *testing.T) {
+	output := FormatMarkdownTable(
+		[]string{"Title", "ID", "Last Edited"},
+		[][]string{
+			{"a | b", "1", "2024-01-01T00:00:00Z"},
+			{`back\slash \| pipe`, "2", ""},
+			{"line one\nline two", "3", "x"},
+		},
+	)
+
+	want, err := os.ReadFile(filepath.Join("testdata", "markdown_table.golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output != string(want) {
+		t.Errorf("FormatMarkdownTable =\n%s\nwant\n%s", output, want)
+	}
+}
+
+func TestFormatPageQuotesFrontmatter(t *testing.T) {
+	output := FormatPage(&PageOutput{
+		Title: `Say "hi"`,
+		URL:   "https://www.notion.so/page",
+		Properties: map[string]string{
+			"Due: date": "2024-06-01",
+			"#tag":      "a, b",
+			"- lead":    "x",
+			"? key":     "<b>&",
+			"Notes":     "line 1\nline 2 😀",
+		},
+		Content: "Body\n",
+	})
+
+	want := `---
+title: "Say \"hi\""
+url: https://www.notion.so/page
+"#tag": "a, b"
+"- lead": "x"
+"? key": "<b>&"
+"Due: date": "2024-06-01"
+"Notes": "line 1\nline 2 😀"
+---
+
+Body
+`
+	if output != want {
+		t.Errorf("FormatPage =\n%s\nwant\n%s", output, want)
+	}
+}
+
+func TestFormatPageFrontmatterRoundTrip(t *testing.T) {
+	props := map[string]string{
+		"Due: date": "2024-06-01",
+		"#tag":      `quoted "value"`,
+		"Notes":     `back\slash`,
+	}
+	output := FormatPage(&PageOutput{Title: "T: x", URL: "https://www.notion.so/page", Properties: props})
+
+	parsed, err := ParseInput(strings.NewReader(output))
+	if err != nil {
+		t.Fatalf("ParseInput: %v", err)
+	}
+
+	want := map[string]interface{}{"title": "T: x"}
+	for name, value := range props {
+		want[name] = value
+	}
+	if len(parsed.Properties) != len(want) {
+		t.Errorf("parsed %d properties, want %d: %v", len(parsed.Properties), len(want), parsed.Properties)
+	}
+	for name, value := range want {
+		if parsed.Properties[name] != value {
+			t.Errorf("property %q = %q, want %q", name, parsed.Properties[name], value)
+		}
+	}
+}
@@ -93,8 +93,8 @@ func parseFrontmatterInput(text string) (*ParsedInput, error) {
 	for k, v := range props {
 		if k == "title" {
 			result.Properties["title"] = v
-		} else if k != "url" {
-			// Skip url (it's metadata, not a property to set)
+		} else if k != "url" && k != "public_url" {
+			// Skip url and public_url (they're metadata, not properties to set)
 			result.Properties[k] = v
 		}
 	}
@@ -106,7 +106,8 @@ func parseFrontmatterInput(text string) (*ParsedInput, error) {
 	return result, nil
 }
 
-// parseFrontmatterProperties parses simple "key: value" lines from frontmatter
+// parseFrontmatterProperties parses simple "key: value" lines from frontmatter.
+// Keys and values may be double-quoted, as FormatPage writes them.
 func parseFrontmatterProperties(frontmatter string) map[string]string {
 	props := make(map[string]string)
 	scanner := bufio.NewScanner(strings.NewReader(frontmatter))
@@ -117,16 +118,30 @@ func parseFrontmatterProperties(frontmatter string) map[string]string {
 			continue
 		}
 
-		idx := strings.Index(line, ":")
-		if idx == -1 {
-			continue
+		var key, value string
+		if strings.HasPrefix(line, `"`) {
+			quoted, rest, ok := cutQuoted(line)
+			if !ok {
+				continue
+			}
+			rest, ok = strings.CutPrefix(strings.TrimSpace(rest), ":")
+			if !ok {
+				continue
+			}
+			key, value = quoted, strings.TrimSpace(rest)
+		} else {
+			idx := strings.Index(line, ":")
+			if idx == -1 {
+				continue
+			}
+			key = strings.TrimSpace(line[:idx])
+			value = strings.TrimSpace(line[idx+1:])
 		}
 
-		key := strings.TrimSpace(line[:idx])
-		value := strings.TrimSpace(line[idx+1:])
-
 		// Remove surrounding quotes
-		if len(value) >= 2 && ((value[0] == '"' && value[len(value)-1] == '"') || (value[0] == '\'' && value[len(value)-1] == '\'')) {
+		if unquoted, rest, ok := cutQuoted(value); ok && rest == "" {
+			value = unquoted
+		} else if len(value) >= 2 && ((value[0] == '"' && value[len(value)-1] == '"') || (value[0] == '\'' && value[len(value)-1] == '\'')) {
 			value = value[1 : len(value)-1]
 		}
 
@@ -135,3 +150,17 @@ func parseFrontmatterProperties(frontmatter string) map[string]string {
 
 	return props
 }
+
+// cutQuoted decodes the double-quoted string at the start of s and returns it
+// with the rest of s
+func cutQuoted(s string) (string, string, bool) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", s, false
+	}
+	dec := json.NewDecoder(strings.NewReader(s))
+	var quoted string
+	if err := dec.Decode(&quoted); err != nil {
+		return "", s, false
+	}
+	return quoted, s[dec.InputOffset():], true
+}
@@ -0,0 +1,48 @@
+package gotion
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// JSONLWriter writes records as JSON Lines
+type JSONLWriter struct {
+	w     *bufio.Writer
+	flush bool
+}
+
+// NewJSONLWriter creates a JSONLWriter. If flush is true, each record is
+// flushed as soon as it is written so pipe consumers see it immediately.
+func NewJSONLWriter(w io.Writer, flush bool) *JSONLWriter {
+	return &JSONLWriter{
+		w:     bufio.NewWriter(w),
+		flush: flush,
+	}
+}
+
+// Write writes a single record followed by a newline
+func (j *JSONLWriter) Write(record interface{}) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal record: %w", err)
+	}
+
+	if _, err := j.w.Write(data); err != nil {
+		return err
+	}
+	if err := j.w.WriteByte('\n'); err != nil {
+		return err
+	}
+
+	if j.flush {
+		return j.w.Flush()
+	}
+	return nil
+}
+
+// Flush writes any buffered records
+func (j *JSONLWriter) Flush() error {
+	return j.w.Flush()
+}
@@ -0,0 +1,44 @@
+package gotion
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestJSONLWriterFlushesEachRecord(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewJSONLWriter(&buf, true)
+
+	records := []map[string]string{{"id": "a"}, {"id": "b"}, {"id": "c"}}
+	var want string
+	for _, record := range records {
+		if err := w.Write(record); err != nil {
+			t.Fatalf("Write: %v", err)
+		}
+		want += `{"id":"` + record["id"] + `"}` + "\n"
+
+		// Each record reaches the underlying writer before the next is written
+		if buf.String() != want {
+			t.Fatalf("after writing %v, output = %q, want %q", record, buf.String(), want)
+		}
+	}
+}
+
+func TestJSONLWriterBuffersWithoutFlush(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewJSONLWriter(&buf, false)
+
+	if err := w.Write(map[string]string{"id": "a"}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("output = %q before Flush, want nothing", buf.String())
+	}
+
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush: %v", err)
+	}
+	if want := "{\"id\":\"a\"}\n"; buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
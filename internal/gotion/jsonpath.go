@@ -0,0 +1,150 @@
+package gotion
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// jsonPathSegment is one step of a JSONPath: a key, an index or a wildcard
+type jsonPathSegment struct {
+	key      string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+// EvalJSONPath evaluates a JSONPath expression over JSON data and returns the matched values.
+// Only a minimal subset is supported: the root $, child keys (.name or ['name']),
+// array indexes ([0], negative from the end) and wildcards (.* or [*]).
+func EvalJSONPath(data []byte, path string) ([]json.RawMessage, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var root interface{}
+	if err := dec.Decode(&root); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON: %w", err)
+	}
+
+	nodes := []interface{}{root}
+	for _, seg := range segments {
+		var next []interface{}
+		for _, node := range nodes {
+			next = append(next, seg.apply(node)...)
+		}
+		nodes = next
+	}
+
+	matches := make([]json.RawMessage, 0, len(nodes))
+	for _, node := range nodes {
+		value, err := json.Marshal(node)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal match: %w", err)
+		}
+		matches = append(matches, value)
+	}
+	return matches, nil
+}
+
+// apply returns the children of node selected by the segment
+func (s jsonPathSegment) apply(node interface{}) []interface{} {
+	switch v := node.(type) {
+	case map[string]interface{}:
+		if s.wildcard {
+			var children []interface{}
+			for _, key := range slices.Sorted(maps.Keys(v)) {
+				children = append(children, v[key])
+			}
+			return children
+		}
+		if child, ok := v[s.key]; ok && !s.isIndex {
+			return []interface{}{child}
+		}
+	case []interface{}:
+		if s.wildcard {
+			return v
+		}
+		if s.isIndex {
+			i := s.index
+			if i < 0 {
+				i += len(v)
+			}
+			if i >= 0 && i < len(v) {
+				return []interface{}{v[i]}
+			}
+		}
+	}
+	return nil
+}
+
+// parseJSONPath splits a JSONPath expression into segments
+func parseJSONPath(path string) ([]jsonPathSegment, error) {
+	path = strings.TrimSpace(path)
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("invalid JSONPath %q: must start with $", path)
+	}
+
+	var segments []jsonPathSegment
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			if strings.HasPrefix(rest, ".") {
+				return nil, fmt.Errorf("invalid JSONPath %q: recursive descent (..) is not supported", path)
+			}
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := rest[:end]
+			if name == "" {
+				return nil, fmt.Errorf("invalid JSONPath %q: empty key", path)
+			}
+			if name == "*" {
+				segments = append(segments, jsonPathSegment{wildcard: true})
+			} else {
+				segments = append(segments, jsonPathSegment{key: name})
+			}
+			rest = rest[end:]
+		case '[':
+			end := strings.Index(rest, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: missing ]", path)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			seg, err := parseJSONPathBracket(inner)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSONPath %q: %w", path, err)
+			}
+			segments = append(segments, seg)
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q", path, rest[0])
+		}
+	}
+	return segments, nil
+}
+
+// parseJSONPathBracket parses the contents of a [...] segment
+func parseJSONPathBracket(inner string) (jsonPathSegment, error) {
+	if inner == "*" {
+		return jsonPathSegment{wildcard: true}, nil
+	}
+	if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+		return jsonPathSegment{key: inner[1 : len(inner)-1]}, nil
+	}
+	index, err := strconv.Atoi(inner)
+	if err != nil {
+		return jsonPathSegment{}, fmt.Errorf("unsupported selector [%s]", inner)
+	}
+	return jsonPathSegment{index: index, isIndex: true}, nil
+}
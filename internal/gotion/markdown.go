@@ -0,0 +1,161 @@
+package gotion
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// maxRichTextLength is the maximum length of a single Notion rich text object
+const maxRichTextLength = 2000
+
+var numberedItemPattern = regexp.MustCompile(`^\d+\. `)
+
+// ParseMarkdownBlocks converts a minimal subset of Markdown into Notion block objects.
+// Supported: paragraphs, headings (#, ##, ###), bulleted lists (- or *),
+// numbered lists (1.) and fenced code blocks. Inline formatting is kept as plain text.
+// Other constructs (quotes, tables, nested lists, ...) return an error.
+func ParseMarkdownBlocks(markdown string) ([]map[string]interface{}, error) {
+	var blocks []map[string]interface{}
+	var paragraph []string
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, textBlock("paragraph", strings.Join(paragraph, " ")))
+			paragraph = nil
+		}
+	}
+
+	scanner := bufio.NewScanner(strings.NewReader(markdown))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	lineNum := 0
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		lineNum++
+
+		// Fenced code block
+		if strings.HasPrefix(line, "```") {
+			flushParagraph()
+			language := strings.TrimSpace(strings.TrimPrefix(line, "```"))
+			start := lineNum
+			var code []string
+			closed := false
+			for scanner.Scan() {
+				lineNum++
+				if strings.HasPrefix(scanner.Text(), "```") {
+					closed = true
+					break
+				}
+				code = append(code, scanner.Text())
+			}
+			if !closed {
+				return nil, fmt.Errorf("line %d: unclosed code fence", start)
+			}
+			blocks = append(blocks, codeBlock(strings.Join(code, "\n"), language))
+			continue
+		}
+
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			flushParagraph()
+			continue
+		}
+
+		if kind := unsupportedMarkdown(line); kind != "" {
+			return nil, fmt.Errorf("line %d: unsupported Markdown: %s", lineNum, kind)
+		}
+
+		switch {
+		case strings.HasPrefix(line, "### "):
+			flushParagraph()
+			blocks = append(blocks, textBlock("heading_3", strings.TrimPrefix(line, "### ")))
+		case strings.HasPrefix(line, "## "):
+			flushParagraph()
+			blocks = append(blocks, textBlock("heading_2", strings.TrimPrefix(line, "## ")))
+		case strings.HasPrefix(line, "# "):
+			flushParagraph()
+			blocks = append(blocks, textBlock("heading_1", strings.TrimPrefix(line, "# ")))
+		case strings.HasPrefix(line, "- "), strings.HasPrefix(line, "* "):
+			flushParagraph()
+			blocks = append(blocks, textBlock("bulleted_list_item", line[2:]))
+		case numberedItemPattern.MatchString(line):
+			flushParagraph()
+			blocks = append(blocks, textBlock("numbered_list_item", numberedItemPattern.ReplaceAllString(line, "")))
+		default:
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read markdown: %w", err)
+	}
+
+	flushParagraph()
+	return blocks, nil
+}
+
+// unsupportedMarkdown returns a description of the Markdown construct on line
+// if it is not supported by ParseMarkdownBlocks, or "" otherwise
+func unsupportedMarkdown(line string) string {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case strings.HasPrefix(line, "####"):
+		return "heading level 4 or deeper"
+	case strings.HasPrefix(trimmed, ">"):
+		return "blockquote"
+	case strings.HasPrefix(trimmed, "|"):
+		return "table"
+	case strings.HasPrefix(trimmed, "!["):
+		return "image"
+	case trimmed == "---" || trimmed == "***" || trimmed == "___":
+		return "horizontal rule"
+	case line != trimmed && (strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* ") || numberedItemPattern.MatchString(trimmed)):
+		return "nested list"
+	}
+	return ""
+}
+
+// textBlock builds a block of the given type holding plain text
+func textBlock(blockType, text string) map[string]interface{} {
+	return map[string]interface{}{
+		"object": "block",
+		"type":   blockType,
+		blockType: map[string]interface{}{
+			"rich_text": richText(text),
+		},
+	}
+}
+
+// codeBlock builds a code block; an empty language is sent as "plain text"
+func codeBlock(code, language string) map[string]interface{} {
+	if language == "" {
+		language = "plain text"
+	}
+	return map[string]interface{}{
+		"object": "block",
+		"type":   "code",
+		"code": map[string]interface{}{
+			"rich_text": richText(code),
+			"language":  language,
+		},
+	}
+}
+
+// richText splits text into rich text objects within Notion's length limit
+func richText(text string) []interface{} {
+	items := []interface{}{}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		n := min(len(runes), maxRichTextLength)
+		items = append(items, map[string]interface{}{
+			"type": "text",
+			"text": map[string]interface{}{
+				"content": string(runes[:n]),
+			},
+		})
+		runes = runes[n:]
+	}
+	return items
+}
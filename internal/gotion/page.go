@@ -1,21 +1,45 @@
 package gotion
 
 import (
+	"net/url"
 	"regexp"
 	"strings"
 )
 
-// ExtractPageID extracts a page ID from a Notion URL or returns the input as-is if it's already an ID.
+// pageIDPattern matches a Notion ID as 32 hex characters, with or without hyphens
+var pageIDPattern = regexp.MustCompile(`(?i)[0-9a-f]{8}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{4}-?[0-9a-f]{12}`)
+
+// ExtractPageID extracts a page ID from a Notion URL or ID and returns it as a dashed UUID.
+// It accepts:
+//   - URLs like https://www.notion.so/Workspace/Title-<32hex>
+//   - URLs with a ?p=<32hex> query (a page opened in peek mode)
+//   - dashed UUIDs and bare 32-character hex IDs
+//
+// Input that contains no ID is returned as-is.
 func ExtractPageID(input string) string {
-	// If it's a URL, extract the ID
-	if strings.Contains(input, "notion.so") || strings.Contains(input, "notion.site") {
-		// Match 32-character hex ID (with or without hyphens)
-		re := regexp.MustCompile(`([a-f0-9]{32}|[a-f0-9]{8}-[a-f0-9]{4}-[a-f0-9]{4}-[a-f0-9]{4}-[a-f0-9]{12})`)
-		match := re.FindString(input)
-		if match != "" {
-			return strings.ReplaceAll(match, "-", "")
+	input = strings.TrimSpace(input)
+
+	if u, err := url.Parse(input); err == nil && u.Host != "" {
+		// The ?p= query names the page actually being viewed
+		if id := pageIDPattern.FindString(u.Query().Get("p")); id != "" {
+			return formatUUID(id)
 		}
+		// Otherwise the ID is the last one in the path (after the title slug)
+		if ids := pageIDPattern.FindAllString(u.Path, -1); len(ids) > 0 {
+			return formatUUID(ids[len(ids)-1])
+		}
+		return input
+	}
+
+	if id := pageIDPattern.FindString(input); id != "" {
+		return formatUUID(id)
 	}
-	// Return as-is (assume it's already an ID)
-	return strings.ReplaceAll(input, "-", "")
+
+	return input
+}
+
+// formatUUID formats a 32-character hex ID (with or without hyphens) as a dashed UUID
+func formatUUID(id string) string {
+	hex := strings.ToLower(strings.ReplaceAll(id, "-", ""))
+	return hex[0:8] + "-" + hex[8:12] + "-" + hex[12:16] + "-" + hex[16:20] + "-" + hex[20:32]
 }
@@ -0,0 +1,40 @@
+package gotion
+
+import "testing"
+
+func TestExtractPageID(t *testing.T) {
+	const want = "1a2b3c4d-5e6f-7a8b-9c0d-1e2f3a4b5c6d"
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "bare hex", input: "1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d", want: want},
+		{name: "dashed uuid", input: "1a2b3c4d-5e6f-7a8b-9c0d-1e2f3a4b5c6d", want: want},
+		{name: "uppercase", input: "1A2B3C4D5E6F7A8B9C0D1E2F3A4B5C6D", want: want},
+		{name: "surrounding space", input: "  1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d\n", want: want},
+		{name: "url with title", input: "https://www.notion.so/Workspace-Title-1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d", want: want},
+		{name: "url with workspace path", input: "https://www.notion.so/myworkspace/Page-Title-1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d", want: want},
+		{name: "url without title", input: "https://www.notion.so/1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d", want: want},
+		{name: "url with dashed id", input: "https://www.notion.so/1a2b3c4d-5e6f-7a8b-9c0d-1e2f3a4b5c6d", want: want},
+		{name: "url with view query", input: "https://www.notion.so/Title-1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d?v=ffffffffffffffffffffffffffffffff", want: want},
+		{
+			name:  "peek url",
+			input: "https://www.notion.so/Database-ffffffffffffffffffffffffffffffff?v=eeeeeeeeeeeeeeeeeeeeeeeeeeeeeeee&p=1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d&pm=s",
+			want:  want,
+		},
+		{name: "notion.site url", input: "https://example.notion.site/Title-1a2b3c4d5e6f7a8b9c0d1e2f3a4b5c6d", want: want},
+		{name: "url without id", input: "https://www.notion.so/Title", want: "https://www.notion.so/Title"},
+		{name: "not an id", input: "abc", want: "abc"},
+		{name: "empty", input: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractPageID(tt.input); got != tt.want {
+				t.Errorf("ExtractPageID(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
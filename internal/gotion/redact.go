@@ -0,0 +1,67 @@
+package gotion
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// RedactMask replaces redacted values
+const RedactMask = "****"
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	urlPattern   = regexp.MustCompile(`https?://[^\s"'<>()\[\]]+`)
+)
+
+// RedactProperties replaces the values of the named properties of a raw page
+// object with RedactMask. It reports whether the title property was redacted.
+func RedactProperties(pageJSON []byte, names []string) ([]byte, bool, error) {
+	var page map[string]json.RawMessage
+	if err := json.Unmarshal(pageJSON, &page); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal page: %w", err)
+	}
+
+	var props map[string]map[string]interface{}
+	if err := json.Unmarshal(page["properties"], &props); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal page properties: %w", err)
+	}
+
+	titleRedacted := false
+	for name, prop := range props {
+		if !slices.Contains(names, name) {
+			continue
+		}
+		// The value is held under a key named after the property type
+		if propType, ok := prop["type"].(string); ok {
+			prop[propType] = RedactMask
+			if propType == "title" {
+				titleRedacted = true
+			}
+		}
+	}
+
+	redacted, err := json.Marshal(props)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal page properties: %w", err)
+	}
+	page["properties"] = redacted
+
+	out, err := json.Marshal(page)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal page: %w", err)
+	}
+	return out, titleRedacted, nil
+}
+
+// RedactPatterns masks all email addresses and/or URLs in rendered output
+func RedactPatterns(s string, emails, urls bool) string {
+	if emails {
+		s = emailPattern.ReplaceAllString(s, RedactMask)
+	}
+	if urls {
+		s = urlPattern.ReplaceAllString(s, RedactMask)
+	}
+	return s
+}
@@ -0,0 +1,27 @@
+package gotion
+
+import "testing"
+
+func TestRedactPatterns(t *testing.T) {
+	const input = "Mail alice@example.com or visit https://example.com/a?b=c (docs at http://docs.example.org)."
+
+	tests := []struct {
+		name   string
+		emails bool
+		urls   bool
+		want   string
+	}{
+		{name: "none", want: input},
+		{name: "emails", emails: true, want: "Mail **** or visit https://example.com/a?b=c (docs at http://docs.example.org)."},
+		{name: "urls", urls: true, want: "Mail alice@example.com or visit **** (docs at ****)."},
+		{name: "both", emails: true, urls: true, want: "Mail **** or visit **** (docs at ****)."},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RedactPatterns(input, tt.emails, tt.urls); got != tt.want {
+				t.Errorf("RedactPatterns = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
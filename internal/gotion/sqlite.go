@@ -0,0 +1,161 @@
+package gotion
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLite column types used by WriteSQLiteTable
+const (
+	SQLiteText    = "TEXT"
+	SQLiteReal    = "REAL"
+	SQLiteInteger = "INTEGER"
+)
+
+// SQLiteColumn is a column of a table written by WriteSQLiteTable
+type SQLiteColumn struct {
+	Name string
+	Type string
+}
+
+// WriteSQLiteTable creates or updates table in the SQLite file at path and
+// upserts rows into it. The first column is the primary key. Columns missing
+// from an existing table are added. Values are converted to the column type;
+// empty values are stored as NULL.
+func WriteSQLiteTable(path, table string, columns []SQLiteColumn, rows []map[string]string) error {
+	if len(columns) == 0 {
+		return fmt.Errorf("no columns given")
+	}
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := ensureSQLiteTable(tx, table, columns); err != nil {
+		return err
+	}
+
+	names := make([]string, len(columns))
+	placeholders := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = quoteIdent(col.Name)
+		placeholders[i] = "?"
+	}
+
+	stmt, err := tx.Prepare(fmt.Sprintf("INSERT OR REPLACE INTO %s (%s) VALUES (%s)",
+		quoteIdent(table), strings.Join(names, ", "), strings.Join(placeholders, ", ")))
+	if err != nil {
+		return fmt.Errorf("failed to prepare insert: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, row := range rows {
+		values := make([]interface{}, len(columns))
+		for i, col := range columns {
+			values[i] = sqliteValue(row[col.Name], col.Type)
+		}
+		if _, err := stmt.Exec(values...); err != nil {
+			return fmt.Errorf("failed to insert row: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// ensureSQLiteTable creates table if needed and adds any missing columns
+func ensureSQLiteTable(tx *sql.Tx, table string, columns []SQLiteColumn) error {
+	defs := make([]string, len(columns))
+	for i, col := range columns {
+		defs[i] = quoteIdent(col.Name) + " " + col.Type
+		if i == 0 {
+			defs[i] += " PRIMARY KEY"
+		}
+	}
+
+	if _, err := tx.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (%s)", quoteIdent(table), strings.Join(defs, ", "))); err != nil {
+		return fmt.Errorf("failed to create table: %w", err)
+	}
+
+	existing, err := sqliteColumns(tx, table)
+	if err != nil {
+		return err
+	}
+
+	for _, col := range columns[1:] {
+		if existing[col.Name] {
+			continue
+		}
+		if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", quoteIdent(table), quoteIdent(col.Name), col.Type)); err != nil {
+			return fmt.Errorf("failed to add column %q: %w", col.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// sqliteColumns returns the set of column names of table
+func sqliteColumns(tx *sql.Tx, table string) (map[string]bool, error) {
+	rows, err := tx.Query(fmt.Sprintf("SELECT name FROM pragma_table_info(%s)", quoteString(table)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read table schema: %w", err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]bool)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("failed to read table schema: %w", err)
+		}
+		columns[name] = true
+	}
+	return columns, rows.Err()
+}
+
+// sqliteValue converts a string value to the column type, falling back to text
+func sqliteValue(value, colType string) interface{} {
+	if value == "" {
+		return nil
+	}
+
+	switch colType {
+	case SQLiteReal:
+		if f, err := strconv.ParseFloat(value, 64); err == nil {
+			return f
+		}
+	case SQLiteInteger:
+		if b, err := strconv.ParseBool(value); err == nil {
+			if b {
+				return 1
+			}
+			return 0
+		}
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return n
+		}
+	}
+	return value
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func quoteString(s string) string {
+	return `'` + strings.ReplaceAll(s, `'`, `''`) + `'`
+}
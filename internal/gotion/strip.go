@@ -0,0 +1,52 @@
+package gotion
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// DefaultStripFields are the ID fields removed by StripJSONFields by default
+var DefaultStripFields = []string{"id", "request_id"}
+
+// StripJSONFields recursively removes object keys named in fields from JSON data.
+// The result is indented with sorted keys so that exports differing only in
+// the stripped fields produce identical output.
+func StripJSONFields(data []byte, fields []string) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON: %w", err)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(stripFields(v, fields)); err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func stripFields(v interface{}, fields []string) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if slices.Contains(fields, key) {
+				delete(v, key)
+				continue
+			}
+			v[key] = stripFields(value, fields)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = stripFields(value, fields)
+		}
+	}
+	return v
+}
@@ -0,0 +1,43 @@
+package gotion
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// TemplateData is the data passed to --template output templates
+type TemplateData struct {
+	ID         string
+	Title      string
+	URL        string
+	Props      map[string]string // Property values as plain text (get only)
+	LastEdited string            // RFC 3339 timestamp (API only)
+}
+
+// ParseOutputTemplate parses a --template output template
+func ParseOutputTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		return nil, fmt.Errorf("--template is required with --format template")
+	}
+
+	tmpl, err := template.New("output").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// ExecuteOutputTemplate renders data with tmpl, followed by a newline
+func ExecuteOutputTemplate(tmpl *template.Template, data *TemplateData) (string, error) {
+	if data.Props == nil {
+		data.Props = map[string]string{}
+	}
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("failed to execute template: %w", err)
+	}
+	sb.WriteString("\n")
+	return sb.String(), nil
+}
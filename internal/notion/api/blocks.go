@@ -0,0 +1,202 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/longkey1/gotion/internal/gotion"
+	"github.com/longkey1/gotion/internal/notion/types"
+)
+
+const (
+	// DefaultBlockConcurrency is the default number of concurrent block children requests
+	DefaultBlockConcurrency = 3
+
+	// maxAppendBlocks is the maximum number of blocks per append request
+	maxAppendBlocks = 100
+)
+
+// blockFetcher fetches nested block children with bounded concurrency.
+// The first error cancels all outstanding requests.
+type blockFetcher struct {
+	client  *Client
+	sem     chan struct{}
+	cancel  context.CancelFunc
+	errOnce sync.Once
+	err     error
+}
+
+// getAllBlockChildren fetches all block children with pagination and recursively fetches nested children,
+// with at most concurrency requests at once
+func (c *Client) getAllBlockChildren(ctx context.Context, blockID string, concurrency int) ([]json.RawMessage, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	if concurrency < 1 {
+		concurrency = DefaultBlockConcurrency
+	}
+	f := &blockFetcher{
+		client: c,
+		sem:    make(chan struct{}, concurrency),
+		cancel: cancel,
+	}
+
+	blocks, err := f.fetchChildren(ctx, blockID)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return blocks, err
+}
+
+// fetchChildren fetches the children of blockID, fetching nested children concurrently.
+// Block order is preserved.
+func (f *blockFetcher) fetchChildren(ctx context.Context, blockID string) ([]json.RawMessage, error) {
+	var rawBlocks []json.RawMessage
+	var cursor string
+
+	for {
+		blocksURL := fmt.Sprintf("%s/blocks/%s/children", baseURL, blockID)
+		if cursor != "" {
+			blocksURL += "?start_cursor=" + cursor
+		}
+
+		body, err := f.get(ctx, blocksURL)
+		if err != nil {
+			return nil, err
+		}
+
+		var blocksResp blocksResponse
+		if err := json.Unmarshal(body, &blocksResp); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal blocks response: %w", err)
+		}
+		rawBlocks = append(rawBlocks, blocksResp.Results...)
+
+		if !blocksResp.HasMore {
+			break
+		}
+		cursor = blocksResp.NextCursor
+	}
+
+	blocks := make([]json.RawMessage, len(rawBlocks))
+	errs := make([]error, len(rawBlocks))
+	var wg sync.WaitGroup
+
+	for i, rawBlock := range rawBlocks {
+		var block blockInfo
+		if err := json.Unmarshal(rawBlock, &block); err != nil || !block.HasChildren {
+			blocks[i] = rawBlock // Return as-is if we can't parse or there are no children
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			blocks[i], errs[i] = f.withChildren(ctx, block.ID, rawBlock)
+			if errs[i] != nil {
+				f.fail(errs[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return blocks, nil
+}
+
+// withChildren fetches the children of a block and adds them to it
+func (f *blockFetcher) withChildren(ctx context.Context, blockID string, rawBlock json.RawMessage) (json.RawMessage, error) {
+	children, err := f.fetchChildren(ctx, blockID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch children for block %s: %w", blockID, err)
+	}
+
+	// Parse the original block as a map to add children
+	var blockMap map[string]interface{}
+	if err := json.Unmarshal(rawBlock, &blockMap); err != nil {
+		return rawBlock, nil
+	}
+
+	// Add children to the block
+	blockMap["children"] = children
+
+	// Re-marshal with children included
+	enrichedBlock, err := json.Marshal(blockMap)
+	if err != nil {
+		return rawBlock, nil
+	}
+
+	return enrichedBlock, nil
+}
+
+// get performs a GET request once a concurrency slot is free
+func (f *blockFetcher) get(ctx context.Context, url string) ([]byte, error) {
+	select {
+	case f.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-f.sem }()
+
+	return f.client.doRequest(ctx, http.MethodGet, url, nil)
+}
+
+// fail records the first error and cancels the remaining requests
+func (f *blockFetcher) fail(err error) {
+	f.errOnce.Do(func() {
+		f.err = err
+		f.cancel()
+	})
+}
+
+// AppendBlocks appends block objects to the children of a page or block.
+// Blocks are sent in batches of 100, the API limit per request.
+func (c *Client) AppendBlocks(ctx context.Context, blockID string, blocks []map[string]interface{}) error {
+	childrenURL := fmt.Sprintf("%s/blocks/%s/children", baseURL, gotion.ExtractPageID(blockID))
+
+	for start := 0; start < len(blocks); start += maxAppendBlocks {
+		end := min(start+maxAppendBlocks, len(blocks))
+
+		reqBody := map[string]interface{}{
+			"children": blocks[start:end],
+		}
+		if _, err := c.doRequestJSON(ctx, http.MethodPatch, childrenURL, reqBody, nil); err != nil {
+			return fmt.Errorf("failed to append blocks: %w", withCapabilityHint(err, capabilityInsertContent))
+		}
+	}
+
+	// blockID is usually the page itself
+	invalidatePage(blockID)
+	return nil
+}
+
+// DeleteBlock deletes a block. Notion archives the block rather than removing it.
+func (c *Client) DeleteBlock(ctx context.Context, blockID string) (*types.Block, error) {
+	blockURL := fmt.Sprintf("%s/blocks/%s", baseURL, gotion.ExtractPageID(blockID))
+
+	var block struct {
+		ID       string       `json:"id"`
+		Type     string       `json:"type"`
+		Archived bool         `json:"archived"`
+		Parent   parentObject `json:"parent"`
+	}
+	if _, err := c.doRequestJSON(ctx, http.MethodDelete, blockURL, nil, &block); err != nil {
+		return nil, fmt.Errorf("failed to delete block: %w", withCapabilityHint(err, capabilityUpdateContent))
+	}
+
+	if block.Parent.Type == "page_id" {
+		invalidatePage(block.Parent.PageID)
+	}
+
+	return &types.Block{
+		ID:       block.ID,
+		Type:     block.Type,
+		Archived: block.Archived,
+	}, nil
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// blockTree serves a synthetic tree of blocks: the root has width children,
+// each of which has width children, down to depth levels. Every response is
+// delayed by latency, and the most requests seen at once is recorded.
+type blockTree struct {
+	width    int
+	depth    int
+	latency  time.Duration
+	inFlight atomic.Int32
+	maxSeen  atomic.Int32
+}
+
+func (tr *blockTree) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	n := tr.inFlight.Add(1)
+	defer tr.inFlight.Add(-1)
+	for {
+		seen := tr.maxSeen.Load()
+		if n <= seen || tr.maxSeen.CompareAndSwap(seen, n) {
+			break
+		}
+	}
+	time.Sleep(tr.latency)
+
+	// Paths are /v1/blocks/<id>/children; IDs are "root" or dash-joined indexes
+	parent := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/blocks/"), "/children")
+	level := 0
+	if parent != "root" {
+		level = strings.Count(parent, "-") + 1
+	}
+
+	var results []map[string]interface{}
+	for i := 0; i < tr.width; i++ {
+		id := fmt.Sprint(i)
+		if parent != "root" {
+			id = parent + "-" + id
+		}
+		results = append(results, map[string]interface{}{
+			"object":       "block",
+			"id":           id,
+			"type":         "paragraph",
+			"has_children": level+1 < tr.depth,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"results": results, "has_more": false})
+}
+
+// countBlocks returns the number of blocks in a fetched tree
+func countBlocks(t testing.TB, blocks []json.RawMessage) int {
+	n := 0
+	for _, raw := range blocks {
+		var block struct {
+			Children []json.RawMessage `json:"children"`
+		}
+		if err := json.Unmarshal(raw, &block); err != nil {
+			t.Fatal(err)
+		}
+		n += 1 + countBlocks(t, block.Children)
+	}
+	return n
+}
+
+func TestGetAllBlockChildren(t *testing.T) {
+	for _, concurrency := range []int{1, 3} {
+		t.Run(fmt.Sprintf("concurrency %d", concurrency), func(t *testing.T) {
+			tree := &blockTree{width: 3, depth: 3, latency: time.Millisecond}
+			client := newTestClient(t, 0, tree)
+
+			blocks, err := client.getAllBlockChildren(context.Background(), "root", concurrency)
+			if err != nil {
+				t.Fatalf("getAllBlockChildren: %v", err)
+			}
+
+			if got, want := countBlocks(t, blocks), 3+9+27; got != want {
+				t.Errorf("fetched %d blocks, want %d", got, want)
+			}
+			if got := tree.maxSeen.Load(); got > int32(concurrency) {
+				t.Errorf("%d requests at once, want at most %d", got, concurrency)
+			}
+
+			// Order is preserved at every level
+			var first struct {
+				ID       string `json:"id"`
+				Children []struct {
+					ID string `json:"id"`
+				} `json:"children"`
+			}
+			if err := json.Unmarshal(blocks[0], &first); err != nil {
+				t.Fatal(err)
+			}
+			if first.ID != "0" || len(first.Children) != 3 || first.Children[2].ID != "0-2" {
+				t.Errorf("first block = %+v, want block 0 with children 0-0..0-2", first)
+			}
+		})
+	}
+}
+
+func BenchmarkGetAllBlockChildren(b *testing.B) {
+	for _, bm := range []struct {
+		name        string
+		concurrency int
+	}{
+		{name: "serial", concurrency: 1},
+		{name: "concurrent", concurrency: DefaultBlockConcurrency},
+	} {
+		b.Run(bm.name, func(b *testing.B) {
+			tree := &blockTree{width: 4, depth: 3, latency: 2 * time.Millisecond}
+			client := newTestClient(b, 0, tree)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := client.getAllBlockChildren(context.Background(), "root", bm.concurrency); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+
+	"github.com/longkey1/gotion/internal/gotion"
+	"github.com/longkey1/gotion/internal/notion/cache"
+	"github.com/longkey1/gotion/internal/notion/metrics"
+	"github.com/longkey1/gotion/internal/notion/types"
+)
+
+// pageCacheKey returns the cache key of a page fetched without options
+func pageCacheKey(pageID string) string {
+	return "page:" + gotion.ExtractPageID(pageID)
+}
+
+// searchCacheKey returns the cache key of a search
+func searchCacheKey(query string, opts *types.SearchOptions) string {
+	if opts == nil {
+		opts = &types.SearchOptions{}
+	}
+	return fmt.Sprintf("search:%q:%+v", query, *opts)
+}
+
+// cachedPage returns a copy of the cached page stored under key
+func cachedPage(key string) (*types.PageResult, bool) {
+	if cache.Default == nil {
+		return nil, false
+	}
+	value, ok := cache.Default.Get(key)
+	result, isResult := value.(*types.PageResult)
+	if !ok || !isResult {
+		metrics.CacheLookups.Inc("miss")
+		return nil, false
+	}
+	metrics.CacheLookups.Inc("hit")
+	return copyPageResult(result), true
+}
+
+// cachedSearch returns a copy of the cached search result stored under key
+func cachedSearch(key string) (*types.SearchResult, bool) {
+	if cache.Default == nil {
+		return nil, false
+	}
+	value, ok := cache.Default.Get(key)
+	result, isResult := value.(*types.SearchResult)
+	if !ok || !isResult {
+		metrics.CacheLookups.Inc("miss")
+		return nil, false
+	}
+	metrics.CacheLookups.Inc("hit")
+	return copySearchResult(result), true
+}
+
+// storeCache stores value under key if caching is enabled
+func storeCache(key string, value interface{}) {
+	if cache.Default != nil {
+		cache.Default.Set(key, value)
+	}
+}
+
+// invalidatePage drops the cached page after a write to it
+func invalidatePage(pageID string) {
+	if cache.Default != nil {
+		cache.Default.Delete(pageCacheKey(pageID))
+	}
+}
+
+// copyPageResult copies result so that callers modifying it (e.g. --redact)
+// do not change the cached value
+func copyPageResult(result *types.PageResult) *types.PageResult {
+	c := *result
+	c.RawJSON = append([]byte(nil), result.RawJSON...)
+	c.Props = maps.Clone(result.Props)
+	c.ChildPages = append([]types.ChildPage(nil), result.ChildPages...)
+	return &c
+}
+
+// copySearchResult copies result so that callers filtering it do not change
+// the cached value
+func copySearchResult(result *types.SearchResult) *types.SearchResult {
+	c := *result
+	c.RawJSON = append([]byte(nil), result.RawJSON...)
+	c.Pages = append([]types.PageSummary(nil), result.Pages...)
+	return &c
+}
+
+// pageBlocks fetches the block children of a page, reusing opts.BlockCache
+// while the page's last_edited_time is unchanged
+func (c *Client) pageBlocks(ctx context.Context, pageID, lastEditedTime string, pageBody []byte, opts *types.GetPageOptions) ([]json.RawMessage, error) {
+	var blockCache types.BlockCache
+	if opts != nil && lastEditedTime != "" {
+		blockCache = opts.BlockCache
+	}
+	var concurrency int
+	if opts != nil {
+		concurrency = opts.BlockConcurrency
+	}
+
+	if blockCache != nil {
+		if blocks, ok := blockCache.LoadBlocks(pageID, lastEditedTime); ok {
+			slog.Debug("using cached blocks", "page_id", pageID, "last_edited_time", lastEditedTime)
+			return blocks, nil
+		}
+	}
+
+	blocks, err := c.getAllBlockChildren(ctx, pageID, concurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	if blockCache != nil {
+		// A cache that cannot be written only costs a refetch next time
+		if err := blockCache.SaveBlocks(pageID, lastEditedTime, pageBody, blocks); err != nil {
+			slog.Debug("failed to save cached blocks", "page_id", pageID, "error", err)
+		}
+	}
+	return blocks, nil
+}
@@ -6,46 +6,95 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/longkey1/gotion/internal/gotion"
+	"github.com/longkey1/gotion/internal/notion/httplog"
+	"github.com/longkey1/gotion/internal/notion/retry"
 	"github.com/longkey1/gotion/internal/notion/types"
+	"github.com/longkey1/gotion/internal/version"
 )
 
 const (
-	baseURL       = "https://api.notion.com/v1"
-	notionVersion = "2022-06-28"
+	baseURL = "https://api.notion.com/v1"
+
+	// DefaultNotionVersion is the Notion-Version header used when none is configured
+	DefaultNotionVersion = "2022-06-28"
 )
 
 // Client is a Notion REST API client
 type Client struct {
-	httpClient *http.Client
-	token      string
+	httpClient    *http.Client
+	token         string
+	notionVersion string
+
+	// users caches user names resolved by userName
+	users   map[string]string
+	usersMu sync.Mutex
 }
 
-// NewClient creates a new Notion REST API client
-func NewClient(token string) *Client {
+// NewClient creates a new Notion REST API client.
+// If notionVersion is empty, DefaultNotionVersion is used.
+// timeout bounds each HTTP request as a backstop to context deadlines; zero means no timeout.
+func NewClient(token, notionVersion string, timeout time.Duration) *Client {
+	if notionVersion == "" {
+		notionVersion = DefaultNotionVersion
+	}
 	return &Client{
-		httpClient: &http.Client{},
-		token:      token,
+		httpClient:    &http.Client{Timeout: timeout, Transport: httplog.NewTransport()},
+		token:         token,
+		notionVersion: notionVersion,
 	}
 }
 
-// GetPage retrieves a page by ID including its block children
+// GetPage retrieves a page by ID including its block children.
+// Pages fetched without options are served from cache.Default when set.
 func (c *Client) GetPage(ctx context.Context, pageID string, opts *types.GetPageOptions) (*types.PageResult, error) {
-	pageID = normalizeID(pageID)
+	pageID = gotion.ExtractPageID(pageID)
+	if opts != nil {
+		return c.getPage(ctx, pageID, opts)
+	}
+
+	key := pageCacheKey(pageID)
+	if result, ok := cachedPage(key); ok {
+		return result, nil
+	}
+	result, err := c.getPage(ctx, pageID, nil)
+	if err != nil {
+		return nil, err
+	}
+	storeCache(key, result)
+	return copyPageResult(result), nil
+}
 
+func (c *Client) getPage(ctx context.Context, pageID string, opts *types.GetPageOptions) (*types.PageResult, error) {
 	// Fetch page metadata
 	pageURL := fmt.Sprintf("%s/pages/%s", baseURL, pageID)
 
 	if opts != nil && len(opts.FilterProperties) > 0 {
-		pageURL += "?filter_properties=" + strings.Join(opts.FilterProperties, "&filter_properties=")
+		ids, err := c.resolvePropertyIDs(ctx, pageID, opts.FilterProperties)
+		if err != nil {
+			return nil, err
+		}
+		pageURL += "?filter_properties=" + strings.Join(ids, "&filter_properties=")
 	}
 
 	pageBody, err := c.doRequest(ctx, http.MethodGet, pageURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get page: %w", err)
+		return nil, fmt.Errorf("failed to get page: %w", withCapabilityHint(err, capabilityReadContent))
+	}
+
+	if opts != nil && opts.ResolveUsers {
+		pageBody, err = c.resolvePeople(ctx, pageBody)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	var page pageResponse
@@ -53,127 +102,102 @@ func (c *Client) GetPage(ctx context.Context, pageID string, opts *types.GetPage
 		return nil, fmt.Errorf("failed to unmarshal page response: %w", err)
 	}
 
-	// Fetch all block children (with pagination)
-	blocks, err := c.getAllBlockChildren(ctx, pageID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get block children: %w", err)
-	}
-
-	// Combine page and blocks into a single response
-	combinedResponse := map[string]interface{}{
-		"page":   json.RawMessage(pageBody),
-		"blocks": blocks,
-	}
-
-	combinedJSON, err := json.MarshalIndent(combinedResponse, "", "  ")
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal combined response: %w", err)
-	}
-
+	// The title is taken before filtering so it survives --property-type-filter
 	title := extractTitle(page.Properties)
-	properties := extractProperties(page.Properties)
-
-	result := &types.PageResult{
-		ID:      page.ID,
-		URL:     page.URL,
-		Title:   title,
-		Props:   properties,
-		RawJSON: combinedJSON,
-		Source:  "api",
-	}
-
-	return result, nil
-}
-
-// getAllBlockChildren fetches all block children with pagination and recursively fetches nested children
-func (c *Client) getAllBlockChildren(ctx context.Context, blockID string) ([]json.RawMessage, error) {
-	var allBlocks []json.RawMessage
-	var cursor string
-
-	for {
-		blocksURL := fmt.Sprintf("%s/blocks/%s/children", baseURL, blockID)
-		if cursor != "" {
-			blocksURL += "?start_cursor=" + cursor
-		}
 
-		body, err := c.doRequest(ctx, http.MethodGet, blocksURL, nil)
+	if opts != nil && len(opts.PropertyTypes) > 0 {
+		pageBody, err = filterPropertiesByType(pageBody, opts.PropertyTypes)
 		if err != nil {
 			return nil, err
 		}
-
-		var blocksResp blocksResponse
-		if err := json.Unmarshal(body, &blocksResp); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal blocks response: %w", err)
-		}
-
-		// Process each block and recursively fetch children if needed
-		for _, rawBlock := range blocksResp.Results {
-			block, err := c.processBlockWithChildren(ctx, rawBlock)
-			if err != nil {
-				return nil, err
+		for name, prop := range page.Properties {
+			if !slices.Contains(opts.PropertyTypes, prop.Type) {
+				delete(page.Properties, name)
 			}
-			allBlocks = append(allBlocks, block)
 		}
+	}
 
-		if !blocksResp.HasMore {
-			break
+	// Fetch all block children (with pagination)
+	var blocks []json.RawMessage
+	if opts == nil || !opts.SkipChildren {
+		blocks, err = c.pageBlocks(ctx, pageID, page.LastEditedTime, pageBody, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get block children: %w", withCapabilityHint(err, capabilityReadContent))
 		}
-		cursor = blocksResp.NextCursor
 	}
 
-	return allBlocks, nil
-}
+	// Combine page and blocks into a single response; without children it is the page object alone
+	combinedJSON := pageBody
+	if opts == nil || !opts.SkipChildren {
+		combinedResponse := map[string]interface{}{
+			"page":   json.RawMessage(pageBody),
+			"blocks": blocks,
+		}
 
-// processBlockWithChildren checks if a block has children and recursively fetches them
-func (c *Client) processBlockWithChildren(ctx context.Context, rawBlock json.RawMessage) (json.RawMessage, error) {
-	var block blockInfo
-	if err := json.Unmarshal(rawBlock, &block); err != nil {
-		return rawBlock, nil // Return as-is if we can't parse
+		combinedJSON, err = json.MarshalIndent(combinedResponse, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal combined response: %w", err)
+		}
 	}
 
-	if !block.HasChildren {
-		return rawBlock, nil
+	if err := c.completeTruncatedProperties(ctx, pageID, page.Properties); err != nil {
+		return nil, err
 	}
+	properties := extractProperties(page.Properties)
 
-	// Fetch children recursively
-	children, err := c.getAllBlockChildren(ctx, block.ID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch children for block %s: %w", block.ID, err)
+	var content string
+	if len(blocks) > 0 {
+		content = renderMarkdown(blocks)
 	}
 
-	// Parse the original block as a map to add children
-	var blockMap map[string]interface{}
-	if err := json.Unmarshal(rawBlock, &blockMap); err != nil {
-		return rawBlock, nil
+	// public_url is null for pages that are not published to the web
+	var publicURL string
+	if page.PublicURL != nil {
+		publicURL = *page.PublicURL
 	}
 
-	// Add children to the block
-	blockMap["children"] = children
-
-	// Re-marshal with children included
-	enrichedBlock, err := json.Marshal(blockMap)
-	if err != nil {
-		return rawBlock, nil
+	result := &types.PageResult{
+		ID:             page.ID,
+		URL:            page.URL,
+		PublicURL:      publicURL,
+		Title:          title,
+		LastEditedTime: page.LastEditedTime,
+		Content:        content,
+		Props:          properties,
+		ChildPages:     extractChildPages(blocks),
+		RawJSON:        combinedJSON,
+		Source:         "api",
 	}
 
-	return enrichedBlock, nil
+	return result, nil
 }
 
 // doRequest performs an HTTP request and returns the response body
 func (c *Client) doRequest(ctx context.Context, method, url string, reqBody []byte) ([]byte, error) {
-	var bodyReader io.Reader
-	if reqBody != nil {
-		bodyReader = bytes.NewReader(reqBody)
+	// In a dry run, writes are printed instead of sent
+	if w := types.DryRunWriter(ctx); w != nil && method != http.MethodGet {
+		if err := types.WriteDryRun(w, method+" "+url, json.RawMessage(reqBody)); err != nil {
+			return nil, err
+		}
+		return []byte("{}"), nil
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+	policy := retry.DefaultPolicy
+	policy.Write = isWrite(method, url)
+	resp, err := policy.Do(ctx, c.httpClient, func() (*http.Request, error) {
+		var bodyReader io.Reader
+		if reqBody != nil {
+			bodyReader = bytes.NewReader(reqBody)
+		}
 
-	c.setHeaders(req)
+		req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
 
-	resp, err := c.httpClient.Do(req)
+		c.setHeaders(req)
+		return req, nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
@@ -185,26 +209,83 @@ func (c *Client) doRequest(ctx context.Context, method, url string, reqBody []by
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		var apiErr apiError
-		if err := json.Unmarshal(body, &apiErr); err != nil {
-			return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+		return nil, newAPIError(resp, body)
+	}
+
+	return body, nil
+}
+
+// isWrite reports whether a request changes data. Search and database
+// queries are sent as POST but only read.
+func isWrite(method, url string) bool {
+	switch method {
+	case http.MethodGet:
+		return false
+	case http.MethodPost:
+		return !strings.HasSuffix(url, "/search") && !strings.HasSuffix(url, "/query")
+	default:
+		return true
+	}
+}
+
+// doRequestJSON marshals reqBody (if not nil), performs the request with
+// doRequest and unmarshals the response into out (if not nil). The raw
+// response body is returned for callers that keep it.
+func (c *Client) doRequestJSON(ctx context.Context, method, url string, reqBody, out interface{}) ([]byte, error) {
+	var data []byte
+	if reqBody != nil {
+		var err error
+		data, err = json.Marshal(reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
 		}
-		return nil, &apiErr
 	}
 
+	body, err := c.doRequest(ctx, method, url, data)
+	if err != nil {
+		return nil, err
+	}
+
+	if out != nil {
+		if err := json.Unmarshal(body, out); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+		}
+	}
 	return body, nil
 }
 
-// Search searches for pages
+// Search searches for pages. Results are served from cache.Default when set.
 func (c *Client) Search(ctx context.Context, query string, opts *types.SearchOptions) (*types.SearchResult, error) {
+	key := searchCacheKey(query, opts)
+	if result, ok := cachedSearch(key); ok {
+		return result, nil
+	}
+	result, err := c.search(ctx, query, opts)
+	if err != nil {
+		return nil, err
+	}
+	storeCache(key, result)
+	return copySearchResult(result), nil
+}
+
+func (c *Client) search(ctx context.Context, query string, opts *types.SearchOptions) (*types.SearchResult, error) {
 	url := fmt.Sprintf("%s/search", baseURL)
 
+	objectType := "page"
+	if opts != nil && opts.ObjectType != "" {
+		objectType = opts.ObjectType
+	}
+
 	searchReq := searchRequest{
 		Query: query,
-		Filter: &searchFilter{
-			Value:    "page",
+	}
+
+	// "all" omits the filter so both pages and databases are returned
+	if objectType != "all" {
+		searchReq.Filter = &searchFilter{
+			Value:    objectType,
 			Property: "object",
-		},
+		}
 	}
 
 	if opts != nil {
@@ -214,6 +295,7 @@ func (c *Client) Search(ctx context.Context, query string, opts *types.SearchOpt
 		if opts.StartCursor != "" {
 			searchReq.StartCursor = opts.StartCursor
 		}
+		// Search can only sort by last_edited_time
 		if opts.Sort != "" {
 			searchReq.Sort = &searchSort{
 				Direction: opts.Sort,
@@ -222,49 +304,36 @@ func (c *Client) Search(ctx context.Context, query string, opts *types.SearchOpt
 		}
 	}
 
-	jsonBody, err := json.Marshal(searchReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(jsonBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	c.setHeaders(req)
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	var searchResp searchResponse
+	body, err := c.doRequestJSON(ctx, http.MethodPost, url, searchReq, &searchResp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, withCapabilityHint(err, capabilityReadContent)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		var apiErr apiError
-		if err := json.Unmarshal(body, &apiErr); err != nil {
-			return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	var pages []types.PageSummary
+	for _, raw := range searchResp.Results {
+		// Skip anything that is not a well-formed page or database rather than
+		// emitting an entry with an empty title
+		var item searchResultItem
+		if err := json.Unmarshal(raw, &item); err != nil {
+			slog.Debug("skipping malformed search result", "error", err)
+			continue
+		}
+		if (item.Object != "page" && item.Object != "database") || item.ID == "" {
+			slog.Debug("skipping unexpected search result", "object", item.Object, "id", item.ID, "code", item.Code, "message", item.Message)
+			continue
 		}
-		return nil, &apiErr
-	}
-
-	var searchResp searchResponse
-	if err := json.Unmarshal(body, &searchResp); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
-	}
 
-	var pages []types.PageSummary
-	for _, page := range searchResp.Results {
-		title := extractTitle(page.Properties)
 		pages = append(pages, types.PageSummary{
-			ID:    page.ID,
-			Title: title,
-			URL:   page.URL,
+			ID:             item.ID,
+			Object:         item.Object,
+			Title:          item.title(),
+			URL:            item.URL,
+			LastEditedTime: item.LastEditedTime,
+			CreatedTime:    item.CreatedTime,
+			Parent:         item.Parent.parent(),
+			Properties:     item.Properties,
+			Archived:       item.Archived || item.InTrash,
 		})
 	}
 
@@ -316,12 +385,168 @@ func (c *Client) ToSearchOutput(result *types.SearchResult) *gotion.SearchOutput
 
 // CreatePage is not supported with API backend
 func (c *Client) CreatePage(ctx context.Context, opts *types.CreatePageOptions) (*types.CreatePageResult, error) {
-	return nil, fmt.Errorf("create is not supported with API backend, use MCP backend")
+	return nil, types.UnsupportedError("create is not supported with API backend, use MCP backend")
 }
 
 // UpdatePage is not supported with API backend
 func (c *Client) UpdatePage(ctx context.Context, pageID string, opts *types.UpdatePageOptions) (*types.UpdatePageResult, error) {
-	return nil, fmt.Errorf("update is not supported with API backend, use MCP backend")
+	return nil, types.UnsupportedError("update is not supported with API backend, use MCP backend")
+}
+
+// ArchivePage archives a page by setting its archived flag
+func (c *Client) ArchivePage(ctx context.Context, pageID string) error {
+	pageURL := fmt.Sprintf("%s/pages/%s", baseURL, gotion.ExtractPageID(pageID))
+
+	reqBody := map[string]interface{}{
+		"archived": true,
+	}
+	if _, err := c.doRequestJSON(ctx, http.MethodPatch, pageURL, reqBody, nil); err != nil {
+		return fmt.Errorf("failed to archive page: %w", withCapabilityHint(err, capabilityUpdateContent))
+	}
+
+	invalidatePage(pageID)
+	return nil
+}
+
+// GetDatabase retrieves a database's title and property schema
+func (c *Client) GetDatabase(ctx context.Context, databaseID string) (*types.Database, error) {
+	databaseURL := fmt.Sprintf("%s/databases/%s", baseURL, gotion.ExtractPageID(databaseID))
+
+	var db databaseResponse
+	if _, err := c.doRequestJSON(ctx, http.MethodGet, databaseURL, nil, &db); err != nil {
+		return nil, fmt.Errorf("failed to get database: %w", withCapabilityHint(err, capabilityReadContent))
+	}
+
+	properties := make(map[string]string, len(db.Properties))
+	options := make(map[string][]string)
+	for name, prop := range db.Properties {
+		properties[name] = prop.Type
+		if opts := prop.options(); opts != nil {
+			options[name] = opts
+		}
+	}
+
+	return &types.Database{
+		ID:         db.ID,
+		Title:      joinPlainText(db.Title),
+		Properties: properties,
+		Options:    options,
+	}, nil
+}
+
+// QueryDatabase queries all rows of a database, following pagination
+func (c *Client) QueryDatabase(ctx context.Context, databaseID string, opts *types.QueryDatabaseOptions) (*types.QueryDatabaseResult, error) {
+	queryURL := fmt.Sprintf("%s/databases/%s/query", baseURL, gotion.ExtractPageID(databaseID))
+
+	var rows []types.DatabaseRow
+	var rawRows []json.RawMessage
+	var cursor string
+
+	for {
+		queryReq := databaseQueryRequest{
+			StartCursor: cursor,
+			PageSize:    100,
+		}
+		if opts != nil {
+			queryReq.Filter = opts.Filter
+		}
+
+		var queryResp databaseQueryResponse
+		if _, err := c.doRequestJSON(ctx, http.MethodPost, queryURL, queryReq, &queryResp); err != nil {
+			return nil, fmt.Errorf("failed to query database: %w", withCapabilityHint(err, capabilityReadContent))
+		}
+
+		for _, raw := range queryResp.Results {
+			var page pageResponse
+			if err := json.Unmarshal(raw, &page); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal row: %w", err)
+			}
+			rows = append(rows, types.DatabaseRow{
+				ID:             page.ID,
+				Title:          extractTitle(page.Properties),
+				URL:            page.URL,
+				LastEditedTime: page.LastEditedTime,
+				Props:          extractProperties(page.Properties),
+				Options:        extractOptions(page.Properties),
+			})
+			rawRows = append(rawRows, raw)
+		}
+
+		if !queryResp.HasMore {
+			break
+		}
+		cursor = queryResp.NextCursor
+	}
+
+	if rawRows == nil {
+		rawRows = []json.RawMessage{}
+	}
+	rawJSON, err := json.MarshalIndent(rawRows, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rows: %w", err)
+	}
+
+	return &types.QueryDatabaseResult{
+		Rows:    rows,
+		RawJSON: rawJSON,
+		Source:  "api",
+	}, nil
+}
+
+// ListComments lists all comments on a page, following pagination
+func (c *Client) ListComments(ctx context.Context, pageID string) ([]types.Comment, error) {
+	var comments []types.Comment
+	var cursor string
+
+	for {
+		commentsURL := fmt.Sprintf("%s/comments?block_id=%s", baseURL, gotion.ExtractPageID(pageID))
+		if cursor != "" {
+			commentsURL += "&start_cursor=" + cursor
+		}
+
+		var commentsResp commentsResponse
+		if _, err := c.doRequestJSON(ctx, http.MethodGet, commentsURL, nil, &commentsResp); err != nil {
+			return nil, fmt.Errorf("failed to list comments: %w", withCapabilityHint(err, capabilityReadComments))
+		}
+
+		for _, comment := range commentsResp.Results {
+			comments = append(comments, comment.toComment())
+		}
+
+		if !commentsResp.HasMore {
+			break
+		}
+		cursor = commentsResp.NextCursor
+	}
+
+	return comments, nil
+}
+
+// CreateComment adds a plain text comment to a page
+func (c *Client) CreateComment(ctx context.Context, pageID string, text string) (*types.Comment, error) {
+	commentsURL := fmt.Sprintf("%s/comments", baseURL)
+
+	reqBody := map[string]interface{}{
+		"parent": map[string]interface{}{
+			"page_id": gotion.ExtractPageID(pageID),
+		},
+		"rich_text": []interface{}{
+			map[string]interface{}{
+				"type": "text",
+				"text": map[string]interface{}{
+					"content": text,
+				},
+			},
+		},
+	}
+
+	var comment commentResponse
+	if _, err := c.doRequestJSON(ctx, http.MethodPost, commentsURL, reqBody, &comment); err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", withCapabilityHint(err, capabilityInsertComments))
+	}
+
+	result := comment.toComment()
+	return &result, nil
 }
 
 // FormatPage formats a page result as JSON string
@@ -329,37 +554,117 @@ func (c *Client) FormatPage(result *types.PageResult) (string, error) {
 	return string(result.RawJSON), nil
 }
 
-// FormatSearch formats a search result as JSON string
+// FormatSearch formats a search result as a types.SearchList envelope
 func (c *Client) FormatSearch(result *types.SearchResult) (string, error) {
-	return string(result.RawJSON), nil
+	var raw struct {
+		Results []json.RawMessage `json:"results"`
+	}
+	if err := json.Unmarshal(result.RawJSON, &raw); err != nil {
+		return "", fmt.Errorf("failed to unmarshal search response: %w", err)
+	}
+
+	list := types.NewSearchList(result)
+	if raw.Results != nil {
+		list.Results = raw.Results
+	}
+	return list.Format()
 }
 
 func (c *Client) setHeaders(req *http.Request) {
 	req.Header.Set("Authorization", "Bearer "+c.token)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Notion-Version", notionVersion)
-}
-
-func normalizeID(id string) string {
-	return strings.ReplaceAll(id, "-", "")
+	req.Header.Set("Notion-Version", c.notionVersion)
+	req.Header.Set("User-Agent", version.UserAgent())
 }
 
 // Internal types for API responses
 
 type pageResponse struct {
-	ID         string              `json:"id"`
-	URL        string              `json:"url"`
-	Properties map[string]property `json:"properties"`
+	ID             string              `json:"id"`
+	URL            string              `json:"url"`
+	PublicURL      *string             `json:"public_url"`
+	LastEditedTime string              `json:"last_edited_time"`
+	Properties     map[string]property `json:"properties"`
 }
 
 type property struct {
-	Type     string     `json:"type"`
-	Title    []richText `json:"title,omitempty"`
-	RichText []richText `json:"rich_text,omitempty"`
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Title       []richText      `json:"title,omitempty"`
+	RichText    []richText      `json:"rich_text,omitempty"`
+	Select      *selectOption   `json:"select,omitempty"`
+	Status      *selectOption   `json:"status,omitempty"`
+	MultiSelect []selectOption  `json:"multi_select,omitempty"`
+	Number      *float64        `json:"number,omitempty"`
+	Checkbox    bool            `json:"checkbox,omitempty"`
+	Date        *dateValue      `json:"date,omitempty"`
+	URL         *string         `json:"url,omitempty"`
+	Email       *string         `json:"email,omitempty"`
+	PhoneNumber *string         `json:"phone_number,omitempty"`
+	People      []userResponse  `json:"people,omitempty"`
+	Files       []fileObject    `json:"files,omitempty"`
+	Relation    []relationValue `json:"relation,omitempty"`
+	HasMore     bool            `json:"has_more,omitempty"` // Items were truncated; see GetPageProperty
+}
+
+// fileObject is an uploaded (Notion-hosted) or external file
+type fileObject struct {
+	Name     string   `json:"name,omitempty"`
+	External *fileURL `json:"external,omitempty"`
+	File     *fileURL `json:"file,omitempty"`
+}
+
+type fileURL struct {
+	URL string `json:"url"`
+}
+
+// url returns the external URL, or the Notion-hosted URL (which expires after an hour)
+func (f fileObject) url() string {
+	if f.External != nil {
+		return f.External.URL
+	}
+	if f.File != nil {
+		return f.File.URL
+	}
+	return ""
+}
+
+type dateValue struct {
+	Start string  `json:"start"`
+	End   *string `json:"end"`
+}
+
+type selectOption struct {
+	Name string `json:"name"`
 }
 
 type richText struct {
-	PlainText string `json:"plain_text"`
+	PlainText string        `json:"plain_text"`
+	Href      string        `json:"href,omitempty"`
+	Mention   *mentionValue `json:"mention,omitempty"`
+}
+
+type mentionValue struct {
+	Type string     `json:"type"`
+	Date *dateValue `json:"date,omitempty"`
+}
+
+// text returns the plain text, falling back to a placeholder for mentions
+// whose plain text is blank
+func (t richText) text() string {
+	if t.PlainText != "" || t.Mention == nil {
+		return t.PlainText
+	}
+
+	switch t.Mention.Type {
+	case "date":
+		if t.Mention.Date != nil {
+			return t.Mention.Date.Start
+		}
+	case "page":
+		return "@page"
+	}
+	return ""
 }
 
 type searchRequest struct {
@@ -381,9 +686,124 @@ type searchFilter struct {
 }
 
 type searchResponse struct {
-	Results    []pageResponse `json:"results"`
-	NextCursor string         `json:"next_cursor"`
-	HasMore    bool           `json:"has_more"`
+	Results    []json.RawMessage `json:"results"`
+	NextCursor string            `json:"next_cursor"`
+	HasMore    bool              `json:"has_more"`
+}
+
+// searchResultItem is a page or database in search results.
+// Database properties hold a schema rather than values, so they are kept raw
+// and only decoded for pages.
+type searchResultItem struct {
+	Object         string          `json:"object"`
+	ID             string          `json:"id"`
+	URL            string          `json:"url"`
+	LastEditedTime string          `json:"last_edited_time"`
+	CreatedTime    string          `json:"created_time"`
+	Title          []richText      `json:"title,omitempty"`
+	Properties     json.RawMessage `json:"properties,omitempty"`
+	Parent         parentObject    `json:"parent"`
+	Archived       bool            `json:"archived"`
+	InTrash        bool            `json:"in_trash"`
+
+	// Set when the item is an error object
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// parentObject is the parent of a page, database or block
+type parentObject struct {
+	Type         string `json:"type"`
+	PageID       string `json:"page_id,omitempty"`
+	DatabaseID   string `json:"database_id,omitempty"`
+	DataSourceID string `json:"data_source_id,omitempty"`
+	BlockID      string `json:"block_id,omitempty"`
+}
+
+// parent converts the parent object, returning nil if it has no type
+func (p *parentObject) parent() *types.Parent {
+	if p.Type == "" {
+		return nil
+	}
+
+	var id string
+	switch p.Type {
+	case "page_id":
+		id = p.PageID
+	case "database_id":
+		id = p.DatabaseID
+	case "data_source_id":
+		id = p.DataSourceID
+	case "block_id":
+		id = p.BlockID
+	}
+	return &types.Parent{Type: p.Type, ID: id}
+}
+
+// title returns the plain text title of a search result item
+func (i *searchResultItem) title() string {
+	if i.Object == "database" {
+		return joinPlainText(i.Title)
+	}
+
+	var props map[string]property
+	if err := json.Unmarshal(i.Properties, &props); err != nil {
+		return ""
+	}
+	return extractTitle(props)
+}
+
+type databaseResponse struct {
+	ID         string                            `json:"id"`
+	Title      []richText                        `json:"title"`
+	Properties map[string]databasePropertySchema `json:"properties"`
+}
+
+type databasePropertySchema struct {
+	Type        string         `json:"type"`
+	Select      *optionsSchema `json:"select,omitempty"`
+	MultiSelect *optionsSchema `json:"multi_select,omitempty"`
+	Status      *optionsSchema `json:"status,omitempty"`
+}
+
+// options returns the allowed option names of a select, multi_select or status property
+func (p databasePropertySchema) options() []string {
+	var schema *optionsSchema
+	switch p.Type {
+	case "select":
+		schema = p.Select
+	case "multi_select":
+		schema = p.MultiSelect
+	case "status":
+		schema = p.Status
+	}
+	if schema == nil {
+		return nil
+	}
+
+	names := make([]string, len(schema.Options))
+	for i, opt := range schema.Options {
+		names[i] = opt.Name
+	}
+	return names
+}
+
+type optionsSchema struct {
+	Options []struct {
+		Name string `json:"name"`
+	} `json:"options"`
+}
+
+type databaseQueryRequest struct {
+	Filter      json.RawMessage `json:"filter,omitempty"`
+	StartCursor string          `json:"start_cursor,omitempty"`
+	PageSize    int             `json:"page_size,omitempty"`
+}
+
+type databaseQueryResponse struct {
+	Results    []json.RawMessage `json:"results"`
+	NextCursor string            `json:"next_cursor"`
+	HasMore    bool              `json:"has_more"`
 }
 
 type blocksResponse struct {
@@ -397,50 +817,229 @@ type blockInfo struct {
 	HasChildren bool   `json:"has_children"`
 }
 
-type apiError struct {
-	Status  int    `json:"status"`
-	Code    string `json:"code"`
-	Message string `json:"message"`
+// childBlock is the subset of a block needed to find child pages and databases
+type childBlock struct {
+	ID        string            `json:"id"`
+	Type      string            `json:"type"`
+	ChildPage *childTitle       `json:"child_page,omitempty"`
+	ChildDB   *childTitle       `json:"child_database,omitempty"`
+	Children  []json.RawMessage `json:"children,omitempty"`
+}
+
+type childTitle struct {
+	Title string `json:"title"`
+}
+
+type commentsResponse struct {
+	Results    []commentResponse `json:"results"`
+	NextCursor string            `json:"next_cursor"`
+	HasMore    bool              `json:"has_more"`
 }
 
-func (e *apiError) Error() string {
-	return e.Message
+type commentResponse struct {
+	ID        string `json:"id"`
+	CreatedBy struct {
+		ID string `json:"id"`
+	} `json:"created_by"`
+	CreatedTime string     `json:"created_time"`
+	RichText    []richText `json:"rich_text"`
+}
+
+func (c *commentResponse) toComment() types.Comment {
+	return types.Comment{
+		ID:          c.ID,
+		AuthorID:    c.CreatedBy.ID,
+		CreatedTime: c.CreatedTime,
+		Text:        joinPlainText(c.RichText),
+	}
+}
+
+// extractChildPages walks a block tree and collects child_page and child_database blocks.
+// It does not descend into the child pages and databases themselves.
+func extractChildPages(blocks []json.RawMessage) []types.ChildPage {
+	var children []types.ChildPage
+	for _, raw := range blocks {
+		var block childBlock
+		if err := json.Unmarshal(raw, &block); err != nil {
+			continue
+		}
+
+		switch block.Type {
+		case "child_page":
+			children = append(children, types.ChildPage{ID: block.ID, Type: block.Type, Title: block.ChildPage.Title})
+		case "child_database":
+			children = append(children, types.ChildPage{ID: block.ID, Type: block.Type, Title: block.ChildDB.Title})
+		default:
+			children = append(children, extractChildPages(block.Children)...)
+		}
+	}
+	return children
+}
+
+func joinPlainText(texts []richText) string {
+	var sb strings.Builder
+	for _, text := range texts {
+		sb.WriteString(text.text())
+	}
+	return sb.String()
 }
 
 func extractTitle(props map[string]property) string {
 	for _, prop := range props {
 		if prop.Type == "title" && len(prop.Title) > 0 {
-			var sb strings.Builder
-			for _, text := range prop.Title {
-				sb.WriteString(text.PlainText)
-			}
-			return sb.String()
+			return joinPlainText(prop.Title)
 		}
 	}
 	return ""
 }
 
+// filterPropertiesByType removes properties whose type is not in propTypes from a raw page object
+func filterPropertiesByType(pageBody []byte, propTypes []string) ([]byte, error) {
+	var page map[string]json.RawMessage
+	if err := json.Unmarshal(pageBody, &page); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal page response: %w", err)
+	}
+
+	var props map[string]json.RawMessage
+	if err := json.Unmarshal(page["properties"], &props); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal page properties: %w", err)
+	}
+
+	for name, raw := range props {
+		var prop struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(raw, &prop); err != nil || !slices.Contains(propTypes, prop.Type) {
+			delete(props, name)
+		}
+	}
+
+	filtered, err := json.Marshal(props)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal page properties: %w", err)
+	}
+	page["properties"] = filtered
+
+	return json.Marshal(page)
+}
+
 func extractProperties(props map[string]property) map[string]string {
 	result := make(map[string]string)
 	for name, prop := range props {
 		switch prop.Type {
 		case "title":
 			if len(prop.Title) > 0 {
-				var sb strings.Builder
-				for _, text := range prop.Title {
-					sb.WriteString(text.PlainText)
-				}
-				result[name] = sb.String()
+				result[name] = joinPlainText(prop.Title)
 			}
 		case "rich_text":
 			if len(prop.RichText) > 0 {
-				var sb strings.Builder
-				for _, text := range prop.RichText {
-					sb.WriteString(text.PlainText)
+				result[name] = joinPlainText(prop.RichText)
+			}
+		case "select", "status", "multi_select":
+			if options := propertyOptions(prop); len(options) > 0 {
+				result[name] = strings.Join(options, ", ")
+			}
+		case "number":
+			if prop.Number != nil {
+				result[name] = strconv.FormatFloat(*prop.Number, 'f', -1, 64)
+			}
+		case "checkbox":
+			result[name] = strconv.FormatBool(prop.Checkbox)
+		case "date":
+			// Date ranges use the ISO 8601 interval form start/end
+			if prop.Date != nil {
+				result[name] = prop.Date.Start
+				if prop.Date.End != nil {
+					result[name] += "/" + *prop.Date.End
+				}
+			}
+		case "people":
+			// Fall back to the ID for users whose name is not included
+			var names []string
+			for _, user := range prop.People {
+				if user.Name != "" {
+					names = append(names, user.Name)
+				} else {
+					names = append(names, user.ID)
+				}
+			}
+			if len(names) > 0 {
+				result[name] = strings.Join(names, ", ")
+			}
+		case "relation":
+			var ids []string
+			for _, relation := range prop.Relation {
+				ids = append(ids, relation.ID)
+			}
+			if len(ids) > 0 {
+				result[name] = strings.Join(ids, ", ")
+			}
+		case "url", "email", "phone_number":
+			if value := stringPropertyValue(prop); value != "" {
+				result[name] = value
+			}
+		case "files":
+			// Prefer the name, since Notion-hosted file URLs expire
+			var files []string
+			for _, file := range prop.Files {
+				if file.Name != "" {
+					files = append(files, file.Name)
+				} else if url := file.url(); url != "" {
+					files = append(files, url)
 				}
-				result[name] = sb.String()
+			}
+			if len(files) > 0 {
+				result[name] = strings.Join(files, ", ")
 			}
 		}
 	}
 	return result
 }
+
+// stringPropertyValue returns the value of a url, email or phone_number property
+func stringPropertyValue(prop property) string {
+	var value *string
+	switch prop.Type {
+	case "url":
+		value = prop.URL
+	case "email":
+		value = prop.Email
+	case "phone_number":
+		value = prop.PhoneNumber
+	}
+	if value == nil {
+		return ""
+	}
+	return *value
+}
+
+// extractOptions returns the selected option names of select, status and multi_select properties
+func extractOptions(props map[string]property) map[string][]string {
+	result := make(map[string][]string)
+	for name, prop := range props {
+		switch prop.Type {
+		case "select", "status", "multi_select":
+			result[name] = propertyOptions(prop)
+		}
+	}
+	return result
+}
+
+func propertyOptions(prop property) []string {
+	options := []string{}
+	switch prop.Type {
+	case "select":
+		if prop.Select != nil {
+			options = append(options, prop.Select.Name)
+		}
+	case "status":
+		if prop.Status != nil {
+			options = append(options, prop.Status.Name)
+		}
+	case "multi_select":
+		for _, option := range prop.MultiSelect {
+			options = append(options, option.Name)
+		}
+	}
+	return options
+}
@@ -0,0 +1,180 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/longkey1/gotion/internal/notion/types"
+)
+
+// rewriteTransport sends requests for baseURL to a test server instead
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestClient returns a client whose requests are served by handler
+func newTestClient(t testing.TB, timeout time.Duration, handler http.Handler) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := NewClient("secret_test", "", timeout)
+	client.httpClient.Transport = &rewriteTransport{target: target}
+	return client
+}
+
+// sleepingHandler holds each request until the client goes away
+func sleepingHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The request context is canceled on disconnect only once the body is read
+		_, _ = io.Copy(io.Discard, r.Body)
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	})
+}
+
+func TestContextDeadlineAbortsRequest(t *testing.T) {
+	client := newTestClient(t, 0, sleepingHandler())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.Search(ctx, "", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Search error = %v, want %v", err, context.DeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Search returned after %s, want the deadline to abort it", elapsed)
+	}
+}
+
+func TestClientTimeoutAbortsRequest(t *testing.T) {
+	client := newTestClient(t, 50*time.Millisecond, sleepingHandler())
+
+	start := time.Now()
+	_, err := client.Search(context.Background(), "", nil)
+	if err == nil {
+		t.Fatal("Search succeeded against a server that never answers")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Search returned after %s, want the client timeout to abort it", elapsed)
+	}
+}
+
+func TestExtractFilesProperty(t *testing.T) {
+	tests := []struct {
+		name  string
+		files string
+		want  string
+	}{
+		{
+			name:  "external",
+			files: `[{"name": "", "type": "external", "external": {"url": "https://example.com/a.pdf"}}]`,
+			want:  "https://example.com/a.pdf",
+		},
+		{
+			name:  "external with name",
+			files: `[{"name": "spec.pdf", "type": "external", "external": {"url": "https://example.com/a.pdf"}}]`,
+			want:  "spec.pdf",
+		},
+		{
+			name:  "notion-hosted",
+			files: `[{"name": "photo.png", "type": "file", "file": {"url": "https://s3.example.com/photo.png?X-Amz-Expires=3600", "expiry_time": "2024-06-01T13:00:00.000Z"}}]`,
+			want:  "photo.png",
+		},
+		{
+			name:  "notion-hosted without name",
+			files: `[{"type": "file", "file": {"url": "https://s3.example.com/photo.png", "expiry_time": "2024-06-01T13:00:00.000Z"}}]`,
+			want:  "https://s3.example.com/photo.png",
+		},
+		{
+			name: "several",
+			files: `[
+				{"name": "a.txt", "type": "file", "file": {"url": "https://s3.example.com/a.txt"}},
+				{"name": "", "type": "external", "external": {"url": "https://example.com/b"}}
+			]`,
+			want: "a.txt, https://example.com/b",
+		},
+		{
+			name:  "empty",
+			files: `[]`,
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var props map[string]property
+			raw := `{"Attachments": {"id": "f", "type": "files", "files": ` + tt.files + `}}`
+			if err := json.Unmarshal([]byte(raw), &props); err != nil {
+				t.Fatal(err)
+			}
+
+			got, ok := extractProperties(props)["Attachments"]
+			if got != tt.want {
+				t.Errorf("Attachments = %q, want %q", got, tt.want)
+			}
+			if ok != (tt.want != "") {
+				t.Errorf("Attachments present = %v, want %v", ok, tt.want != "")
+			}
+		})
+	}
+}
+
+func TestSearchSkipsNonPageResults(t *testing.T) {
+	client := newTestClient(t, 0, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{
+			"object": "list",
+			"results": [
+				{"object": "page", "id": "p1", "url": "https://www.notion.so/p1", "properties": {"Name": {"id": "title", "type": "title", "title": [{"plain_text": "First"}]}}},
+				{"object": "error", "status": 500, "code": "internal_server_error", "message": "Unexpected error"},
+				{"object": "database", "id": "d1", "title": [{"plain_text": "Tasks"}]},
+				{"object": "block", "id": "b1"},
+				{"object": "page"},
+				"not an object",
+				{"object": "page", "id": "p2", "properties": {"Name": {"id": "title", "type": "title", "title": [{"plain_text": "Second"}]}}}
+			],
+			"has_more": false,
+			"next_cursor": null
+		}`)
+	}))
+
+	result, err := client.Search(context.Background(), "mixed", &types.SearchOptions{ObjectType: "all"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	want := []struct{ id, title string }{{"p1", "First"}, {"d1", "Tasks"}, {"p2", "Second"}}
+	if len(result.Pages) != len(want) {
+		t.Fatalf("got %d results, want %d: %+v", len(result.Pages), len(want), result.Pages)
+	}
+	for i, w := range want {
+		if result.Pages[i].ID != w.id || result.Pages[i].Title != w.title {
+			t.Errorf("result %d = %s %q, want %s %q", i, result.Pages[i].ID, result.Pages[i].Title, w.id, w.title)
+		}
+	}
+}
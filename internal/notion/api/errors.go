@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Notion API error codes
+const (
+	codeObjectNotFound = "object_not_found"
+	codeUnauthorized   = "unauthorized"
+	codeRateLimited    = "rate_limited"
+)
+
+// APIError is an error response from the Notion API
+type APIError struct {
+	Status    int    `json:"status"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	RequestID string `json:"request_id"` // Quote this to Notion support
+}
+
+func (e *APIError) Error() string {
+	if e.RequestID == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s (status %d, request_id: %s)", e.Message, e.Status, e.RequestID)
+}
+
+// newAPIError builds an APIError from an error response, taking the request ID
+// from the response headers when the body does not include one
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	var apiErr APIError
+	if err := json.Unmarshal(body, &apiErr); err != nil || apiErr.Message == "" {
+		apiErr = APIError{Message: fmt.Sprintf("API error (status %d): %s", resp.StatusCode, string(body))}
+	}
+
+	if apiErr.Status == 0 {
+		apiErr.Status = resp.StatusCode
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get("X-Request-Id")
+	}
+	if apiErr.RequestID == "" {
+		apiErr.RequestID = resp.Header.Get("X-Notion-Request-Id")
+	}
+	return &apiErr
+}
+
+// ErrorCode returns the Notion error code of err (e.g. "object_not_found"),
+// or "" if err is not an API error
+func ErrorCode(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return ""
+}
+
+// IsNotFound reports whether err is an object_not_found error. Notion also
+// returns it for pages that exist but are not shared with the integration.
+func IsNotFound(err error) bool {
+	return ErrorCode(err) == codeObjectNotFound
+}
+
+// IsUnauthorized reports whether err is an unauthorized (invalid token) error
+func IsUnauthorized(err error) bool {
+	return ErrorCode(err) == codeUnauthorized
+}
+
+// IsRateLimited reports whether err is a rate_limited error, returned once retries are exhausted
+func IsRateLimited(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == codeRateLimited || apiErr.Status == http.StatusTooManyRequests
+	}
+	return false
+}
+
+// Integration capabilities, as named in the Notion integration settings
+const (
+	capabilityReadContent    = "Read content"
+	capabilityUpdateContent  = "Update content"
+	capabilityInsertContent  = "Insert content"
+	capabilityReadComments   = "Read comments"
+	capabilityInsertComments = "Insert comments"
+	capabilityReadUsers      = "Read user information"
+)
+
+// withCapabilityHint turns a restricted_resource error into actionable guidance
+// naming the integration capability the operation requires
+func withCapabilityHint(err error, capability string) error {
+	if ErrorCode(err) == "restricted_resource" {
+		return fmt.Errorf("%w (your integration lacks the '%s' capability; enable it in the integration's settings at https://www.notion.so/my-integrations)", err, capability)
+	}
+	return err
+}
@@ -10,6 +10,9 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/longkey1/gotion/internal/notion/httplog"
+	"github.com/longkey1/gotion/internal/version"
 )
 
 const (
@@ -34,6 +37,8 @@ type OAuthToken struct {
 	WorkspaceIcon        string    `json:"workspace_icon"`
 	DuplicatedTemplateID string    `json:"duplicated_template_id,omitempty"`
 	Owner                *Owner    `json:"owner,omitempty"`
+	RefreshToken         string    `json:"refresh_token,omitempty"`
+	ExpiresIn            int64     `json:"expires_in,omitempty"`
 	ExpiresAt            time.Time `json:"-"`
 }
 
@@ -59,7 +64,7 @@ type OAuthClient struct {
 func NewOAuthClient(config *OAuthConfig) *OAuthClient {
 	return &OAuthClient{
 		config:     config,
-		httpClient: &http.Client{Timeout: 30 * time.Second},
+		httpClient: &http.Client{Timeout: 30 * time.Second, Transport: httplog.NewTransport()},
 	}
 }
 
@@ -84,6 +89,25 @@ func (c *OAuthClient) ExchangeCode(ctx context.Context, code string) (*OAuthToke
 	data.Set("code", code)
 	data.Set("redirect_uri", c.config.RedirectURI)
 
+	return c.requestToken(ctx, data)
+}
+
+// RefreshToken refreshes an access token using a refresh token
+func RefreshToken(ctx context.Context, clientID, clientSecret, refreshToken string) (*OAuthToken, error) {
+	client := NewOAuthClient(&OAuthConfig{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+	})
+
+	data := url.Values{}
+	data.Set("grant_type", "refresh_token")
+	data.Set("refresh_token", refreshToken)
+
+	return client.requestToken(ctx, data)
+}
+
+// requestToken posts a token request to the token endpoint and decodes the response
+func (c *OAuthClient) requestToken(ctx context.Context, data url.Values) (*OAuthToken, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(data.Encode()))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -93,6 +117,7 @@ func (c *OAuthClient) ExchangeCode(ctx context.Context, code string) (*OAuthToke
 	auth := base64.StdEncoding.EncodeToString([]byte(c.config.ClientID + ":" + c.config.ClientSecret))
 	req.Header.Set("Authorization", "Basic "+auth)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", version.UserAgent())
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -106,7 +131,7 @@ func (c *OAuthClient) ExchangeCode(ctx context.Context, code string) (*OAuthToke
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		var apiErr apiError
+		var apiErr APIError
 		if err := json.Unmarshal(body, &apiErr); err != nil {
 			return nil, fmt.Errorf("OAuth error (status %d): %s", resp.StatusCode, string(body))
 		}
@@ -118,5 +143,9 @@ func (c *OAuthClient) ExchangeCode(ctx context.Context, code string) (*OAuthToke
 		return nil, fmt.Errorf("failed to unmarshal token: %w", err)
 	}
 
+	if token.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
 	return &token, nil
 }
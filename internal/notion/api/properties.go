@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/longkey1/gotion/internal/gotion"
+)
+
+// propertyItemList is a page of items of a paginated page property
+type propertyItemList struct {
+	Results    []json.RawMessage `json:"results"`
+	NextCursor string            `json:"next_cursor"`
+	HasMore    bool              `json:"has_more"`
+}
+
+// propertyItem is a single item of a relation or people property
+type propertyItem struct {
+	Type     string        `json:"type"`
+	Relation relationValue `json:"relation"`
+	People   userResponse  `json:"people"`
+}
+
+type relationValue struct {
+	ID string `json:"id"`
+}
+
+// GetPageProperty retrieves all items of a page property, following pagination.
+// The page object includes at most 25 items of relation, people, title and
+// rich_text properties; this endpoint returns them all.
+func (c *Client) GetPageProperty(ctx context.Context, pageID, propID string) ([]json.RawMessage, error) {
+	var items []json.RawMessage
+	var cursor string
+
+	for {
+		propertyURL := fmt.Sprintf("%s/pages/%s/properties/%s?page_size=100", baseURL, gotion.ExtractPageID(pageID), propID)
+		if cursor != "" {
+			propertyURL += "&start_cursor=" + url.QueryEscape(cursor)
+		}
+
+		var list propertyItemList
+		if _, err := c.doRequestJSON(ctx, http.MethodGet, propertyURL, nil, &list); err != nil {
+			return nil, fmt.Errorf("failed to get page property: %w", withCapabilityHint(err, capabilityReadContent))
+		}
+		items = append(items, list.Results...)
+
+		if !list.HasMore || list.NextCursor == "" {
+			break
+		}
+		cursor = list.NextCursor
+	}
+
+	return items, nil
+}
+
+// resolvePropertyIDs translates property names to the IDs that filter_properties
+// expects, fetching the page once to learn them. Values that do not name a
+// property are passed through as IDs.
+func (c *Client) resolvePropertyIDs(ctx context.Context, pageID string, namesOrIDs []string) ([]string, error) {
+	pageURL := fmt.Sprintf("%s/pages/%s", baseURL, pageID)
+
+	var page pageResponse
+	if _, err := c.doRequestJSON(ctx, http.MethodGet, pageURL, nil, &page); err != nil {
+		return nil, fmt.Errorf("failed to get page properties: %w", withCapabilityHint(err, capabilityReadContent))
+	}
+
+	ids := make([]string, len(namesOrIDs))
+	for i, v := range namesOrIDs {
+		if prop, ok := page.Properties[v]; ok && prop.ID != "" {
+			ids[i] = prop.ID
+		} else {
+			ids[i] = v
+		}
+	}
+	return ids, nil
+}
+
+// completeTruncatedProperties replaces relation and people properties that the
+// page object truncated (has_more) with their full list of items
+func (c *Client) completeTruncatedProperties(ctx context.Context, pageID string, props map[string]property) error {
+	for name, prop := range props {
+		if !prop.HasMore || (prop.Type != "relation" && prop.Type != "people") {
+			continue
+		}
+
+		items, err := c.GetPageProperty(ctx, pageID, prop.ID)
+		if err != nil {
+			return err
+		}
+
+		prop.Relation = nil
+		prop.People = nil
+		for _, raw := range items {
+			var item propertyItem
+			if err := json.Unmarshal(raw, &item); err != nil {
+				return fmt.Errorf("failed to unmarshal property item: %w", err)
+			}
+			switch item.Type {
+			case "relation":
+				prop.Relation = append(prop.Relation, item.Relation)
+			case "people":
+				prop.People = append(prop.People, item.People)
+			}
+		}
+		prop.HasMore = false
+		props[name] = prop
+	}
+	return nil
+}
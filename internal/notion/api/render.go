@@ -0,0 +1,187 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// markdownBlock is the subset of a block needed to render it as Markdown
+type markdownBlock struct {
+	ID       string            `json:"id"`
+	Type     string            `json:"type"`
+	Children []json.RawMessage `json:"children,omitempty"`
+
+	Paragraph        textBlockValue `json:"paragraph"`
+	Heading1         textBlockValue `json:"heading_1"`
+	Heading2         textBlockValue `json:"heading_2"`
+	Heading3         textBlockValue `json:"heading_3"`
+	BulletedListItem textBlockValue `json:"bulleted_list_item"`
+	NumberedListItem textBlockValue `json:"numbered_list_item"`
+	ToDo             textBlockValue `json:"to_do"`
+	Toggle           textBlockValue `json:"toggle"`
+	Quote            textBlockValue `json:"quote"`
+	Callout          textBlockValue `json:"callout"`
+	Code             textBlockValue `json:"code"`
+	Equation         struct {
+		Expression string `json:"expression"`
+	} `json:"equation"`
+	Bookmark  linkBlockValue `json:"bookmark"`
+	Embed     linkBlockValue `json:"embed"`
+	Image     fileBlockValue `json:"image"`
+	File      fileBlockValue `json:"file"`
+	PDF       fileBlockValue `json:"pdf"`
+	ChildPage childTitle     `json:"child_page"`
+	ChildDB   childTitle     `json:"child_database"`
+}
+
+type textBlockValue struct {
+	RichText []richText `json:"rich_text"`
+	Checked  bool       `json:"checked,omitempty"`
+	Language string     `json:"language,omitempty"`
+}
+
+type linkBlockValue struct {
+	URL     string     `json:"url"`
+	Caption []richText `json:"caption,omitempty"`
+}
+
+type fileBlockValue struct {
+	fileObject
+	Caption []richText `json:"caption,omitempty"`
+}
+
+// renderMarkdown renders a block tree as Markdown.
+// Child pages and databases are rendered as links and not descended into.
+// Unsupported block types are skipped.
+func renderMarkdown(blocks []json.RawMessage) string {
+	var sb strings.Builder
+	renderBlocks(&sb, blocks, "")
+	return strings.TrimSpace(sb.String()) + "\n"
+}
+
+func renderBlocks(sb *strings.Builder, blocks []json.RawMessage, indent string) {
+	number := 0
+	for _, raw := range blocks {
+		var block markdownBlock
+		if err := json.Unmarshal(raw, &block); err != nil {
+			continue
+		}
+
+		if block.Type == "numbered_list_item" {
+			number++
+		} else {
+			number = 0
+		}
+
+		renderBlock(sb, &block, indent, number)
+	}
+}
+
+func renderBlock(sb *strings.Builder, block *markdownBlock, indent string, number int) {
+	// line writes a single line; list items are kept together, other blocks are separated by a blank line
+	line := func(s string, listItem bool) {
+		sb.WriteString(indent + s + "\n")
+		if !listItem {
+			sb.WriteString("\n")
+		}
+	}
+	// children renders nested blocks indented under list items and toggles
+	children := func() {
+		renderBlocks(sb, block.Children, indent+"  ")
+	}
+
+	switch block.Type {
+	case "paragraph":
+		if text := renderRichText(block.Paragraph.RichText); text != "" {
+			line(text, false)
+		}
+	case "heading_1":
+		line("# "+renderRichText(block.Heading1.RichText), false)
+	case "heading_2":
+		line("## "+renderRichText(block.Heading2.RichText), false)
+	case "heading_3":
+		line("### "+renderRichText(block.Heading3.RichText), false)
+	case "bulleted_list_item":
+		line("- "+renderRichText(block.BulletedListItem.RichText), true)
+		children()
+	case "numbered_list_item":
+		line(fmt.Sprintf("%d. %s", number, renderRichText(block.NumberedListItem.RichText)), true)
+		children()
+	case "to_do":
+		check := " "
+		if block.ToDo.Checked {
+			check = "x"
+		}
+		line(fmt.Sprintf("- [%s] %s", check, renderRichText(block.ToDo.RichText)), true)
+		children()
+	case "toggle":
+		line("- "+renderRichText(block.Toggle.RichText), true)
+		children()
+	case "quote":
+		line("> "+renderRichText(block.Quote.RichText), false)
+	case "callout":
+		line("> "+renderRichText(block.Callout.RichText), false)
+	case "code":
+		code := joinPlainText(block.Code.RichText)
+		line("```"+block.Code.Language+"\n"+indent+strings.ReplaceAll(code, "\n", "\n"+indent)+"\n"+indent+"```", false)
+	case "equation":
+		line("$$"+block.Equation.Expression+"$$", false)
+	case "divider":
+		line("---", false)
+	case "bookmark":
+		line(linkLine(block.Bookmark), false)
+	case "embed":
+		line(linkLine(block.Embed), false)
+	case "image":
+		line(fmt.Sprintf("![%s](%s)", joinPlainText(block.Image.Caption), block.Image.url()), false)
+	case "file":
+		line(fmt.Sprintf("[%s](%s)", fileLabel(block.File, "file"), block.File.url()), false)
+	case "pdf":
+		line(fmt.Sprintf("[%s](%s)", fileLabel(block.PDF, "pdf"), block.PDF.url()), false)
+	case "child_page":
+		line(fmt.Sprintf("[%s](%s)", block.ChildPage.Title, notionURL(block.ID)), false)
+	case "child_database":
+		line(fmt.Sprintf("[%s](%s)", block.ChildDB.Title, notionURL(block.ID)), false)
+	default:
+		// Column lists, synced blocks and other containers render their children in place
+		renderBlocks(sb, block.Children, indent)
+	}
+}
+
+// renderRichText renders rich text as Markdown, keeping links
+func renderRichText(texts []richText) string {
+	var sb strings.Builder
+	for _, t := range texts {
+		if t.Href != "" {
+			sb.WriteString(fmt.Sprintf("[%s](%s)", t.text(), t.Href))
+		} else {
+			sb.WriteString(t.text())
+		}
+	}
+	return sb.String()
+}
+
+// linkLine renders a bookmark or embed as a bare link, or as a link labelled with its caption
+func linkLine(l linkBlockValue) string {
+	if caption := joinPlainText(l.Caption); caption != "" {
+		return fmt.Sprintf("[%s](%s)", caption, l.URL)
+	}
+	return fmt.Sprintf("<%s>", l.URL)
+}
+
+// fileLabel returns the caption of a file block, or its file name, or fallback
+func fileLabel(f fileBlockValue, fallback string) string {
+	if caption := joinPlainText(f.Caption); caption != "" {
+		return caption
+	}
+	if f.Name != "" {
+		return f.Name
+	}
+	return fallback
+}
+
+// notionURL returns the workspace URL of a page or database
+func notionURL(id string) string {
+	return "https://www.notion.so/" + strings.ReplaceAll(id, "-", "")
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRenderMediaBlocks(t *testing.T) {
+	tests := []struct {
+		name  string
+		block string
+		want  string
+	}{
+		{
+			name:  "external image with caption",
+			block: `{"type": "image", "image": {"type": "external", "external": {"url": "https://example.com/cat.png"}, "caption": [{"plain_text": "A cat"}]}}`,
+			want:  "![A cat](https://example.com/cat.png)\n",
+		},
+		{
+			name:  "notion-hosted image without caption",
+			block: `{"type": "image", "image": {"type": "file", "file": {"url": "https://s3.example.com/cat.png", "expiry_time": "2024-06-01T13:00:00.000Z"}, "caption": []}}`,
+			want:  "![](https://s3.example.com/cat.png)\n",
+		},
+		{
+			name:  "file with name",
+			block: `{"type": "file", "file": {"type": "file", "name": "report.xlsx", "file": {"url": "https://s3.example.com/report.xlsx"}, "caption": []}}`,
+			want:  "[report.xlsx](https://s3.example.com/report.xlsx)\n",
+		},
+		{
+			name:  "file with caption",
+			block: `{"type": "file", "file": {"type": "external", "name": "report.xlsx", "external": {"url": "https://example.com/report.xlsx"}, "caption": [{"plain_text": "Q2 report"}]}}`,
+			want:  "[Q2 report](https://example.com/report.xlsx)\n",
+		},
+		{
+			name:  "file without name or caption",
+			block: `{"type": "file", "file": {"type": "external", "external": {"url": "https://example.com/x"}}}`,
+			want:  "[file](https://example.com/x)\n",
+		},
+		{
+			name:  "pdf",
+			block: `{"type": "pdf", "pdf": {"type": "external", "external": {"url": "https://example.com/paper.pdf"}}}`,
+			want:  "[pdf](https://example.com/paper.pdf)\n",
+		},
+		{
+			name:  "bookmark",
+			block: `{"type": "bookmark", "bookmark": {"url": "https://example.com", "caption": []}}`,
+			want:  "<https://example.com>\n",
+		},
+		{
+			name:  "bookmark with caption",
+			block: `{"type": "bookmark", "bookmark": {"url": "https://example.com", "caption": [{"plain_text": "Example"}]}}`,
+			want:  "[Example](https://example.com)\n",
+		},
+		{
+			name:  "embed",
+			block: `{"type": "embed", "embed": {"url": "https://example.com/embed"}}`,
+			want:  "<https://example.com/embed>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := renderMarkdown([]json.RawMessage{json.RawMessage(tt.block)})
+			if got != tt.want {
+				t.Errorf("renderMarkdown = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
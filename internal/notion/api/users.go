@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type userResponse struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Me returns the bot user of the token, which confirms that the token works
+func (c *Client) Me(ctx context.Context) (*User, error) {
+	var user User
+	if _, err := c.doRequestJSON(ctx, http.MethodGet, baseURL+"/users/me", nil, &user); err != nil {
+		return nil, fmt.Errorf("failed to get bot user: %w", err)
+	}
+	return &user, nil
+}
+
+// userName returns the name of a user, fetching it once per client lifetime
+func (c *Client) userName(ctx context.Context, userID string) (string, error) {
+	c.usersMu.Lock()
+	name, ok := c.users[userID]
+	c.usersMu.Unlock()
+	if ok {
+		return name, nil
+	}
+
+	var user userResponse
+	if _, err := c.doRequestJSON(ctx, http.MethodGet, fmt.Sprintf("%s/users/%s", baseURL, userID), nil, &user); err != nil {
+		return "", fmt.Errorf("failed to get user: %w", withCapabilityHint(err, capabilityReadUsers))
+	}
+
+	c.usersMu.Lock()
+	if c.users == nil {
+		c.users = make(map[string]string)
+	}
+	c.users[userID] = user.Name
+	c.usersMu.Unlock()
+
+	return user.Name, nil
+}
+
+// resolvePeople fills in missing user names in the people properties of a raw page object
+func (c *Client) resolvePeople(ctx context.Context, pageBody []byte) ([]byte, error) {
+	var page map[string]json.RawMessage
+	if err := json.Unmarshal(pageBody, &page); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal page response: %w", err)
+	}
+
+	var props map[string]map[string]interface{}
+	if err := json.Unmarshal(page["properties"], &props); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal page properties: %w", err)
+	}
+
+	changed := false
+	for _, prop := range props {
+		if prop["type"] != "people" {
+			continue
+		}
+		people, _ := prop["people"].([]interface{})
+		for _, p := range people {
+			user, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			id, _ := user["id"].(string)
+			if name, _ := user["name"].(string); name != "" || id == "" {
+				continue
+			}
+
+			name, err := c.userName(ctx, id)
+			if err != nil {
+				return nil, err
+			}
+			user["name"] = name
+			changed = true
+		}
+	}
+
+	if !changed {
+		return pageBody, nil
+	}
+
+	resolved, err := json.Marshal(props)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal page properties: %w", err)
+	}
+	page["properties"] = resolved
+
+	return json.Marshal(page)
+}
@@ -0,0 +1,107 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BlockStore is an on-disk cache of page block trees with one JSON file per
+// page. It implements types.BlockCache.
+type BlockStore struct {
+	dir string
+}
+
+// blockEntry is the file stored for a page: the combined {page, blocks}
+// JSON and the last_edited_time it is valid for
+type blockEntry struct {
+	LastEditedTime string            `json:"last_edited_time"`
+	Page           json.RawMessage   `json:"page"`
+	Blocks         []json.RawMessage `json:"blocks"`
+}
+
+// NewBlockStore creates a block cache storing its files in dir
+func NewBlockStore(dir string) *BlockStore {
+	return &BlockStore{dir: dir}
+}
+
+// LoadBlocks returns the cached blocks of a page last edited at lastEditedTime
+func (s *BlockStore) LoadBlocks(pageID, lastEditedTime string) ([]json.RawMessage, bool) {
+	path, ok := s.path(pageID)
+	if !ok {
+		return nil, false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+
+	var entry blockEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.LastEditedTime != lastEditedTime {
+		return nil, false
+	}
+	return entry.Blocks, true
+}
+
+// SaveBlocks stores the page object and blocks of a page last edited at lastEditedTime
+func (s *BlockStore) SaveBlocks(pageID, lastEditedTime string, page json.RawMessage, blocks []json.RawMessage) error {
+	path, ok := s.path(pageID)
+	if !ok {
+		return fmt.Errorf("invalid page ID for cache: %s", pageID)
+	}
+
+	if blocks == nil {
+		blocks = []json.RawMessage{}
+	}
+	data, err := json.Marshal(blockEntry{
+		LastEditedTime: lastEditedTime,
+		Page:           page,
+		Blocks:         blocks,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal cached blocks: %w", err)
+	}
+
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write to a temporary file first so a concurrent read never sees a partial entry
+	tmp, err := os.CreateTemp(s.dir, pageID+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return nil
+}
+
+// Clear removes all cached entries
+func (s *BlockStore) Clear() error {
+	if err := os.RemoveAll(s.dir); err != nil {
+		return fmt.Errorf("failed to clear cache: %w", err)
+	}
+	return nil
+}
+
+// path returns the cache file of a page. IDs that could escape the cache
+// directory, such as unparsed input, are not cached.
+func (s *BlockStore) path(pageID string) (string, bool) {
+	if pageID == "" || strings.ContainsAny(pageID, `/\.`) {
+		return "", false
+	}
+	return filepath.Join(s.dir, pageID+".json"), true
+}
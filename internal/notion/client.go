@@ -1,7 +1,9 @@
 package notion
 
 import (
+	"context"
 	"fmt"
+	"io"
 
 	"github.com/longkey1/gotion/internal/gotion/config"
 	"github.com/longkey1/gotion/internal/notion/api"
@@ -12,6 +14,7 @@ import (
 // Re-export types for convenience
 type Client = types.Client
 type GetPageOptions = types.GetPageOptions
+type BlockCache = types.BlockCache
 type SearchOptions = types.SearchOptions
 type PageResult = types.PageResult
 type SearchResult = types.SearchResult
@@ -21,9 +24,54 @@ type UpdatePageOptions = types.UpdatePageOptions
 type CreatePageResult = types.CreatePageResult
 type UpdatePageResult = types.UpdatePageResult
 type Parent = types.Parent
+type Comment = types.Comment
+type Block = types.Block
+type ChildPage = types.ChildPage
+type Database = types.Database
+type QueryDatabaseOptions = types.QueryDatabaseOptions
+type QueryDatabaseResult = types.QueryDatabaseResult
+type DatabaseRow = types.DatabaseRow
 
-// NewClient creates a new Notion client based on the config
+// ErrUnsupported is matched by errors returned for operations a backend does not support
+var ErrUnsupported = types.ErrUnsupported
+
+// WithDryRun returns a context in which write operations print their request to w instead of sending it
+func WithDryRun(ctx context.Context, w io.Writer) context.Context {
+	return types.WithDryRun(ctx, w)
+}
+
+// IsNotFound reports whether err is a Notion object_not_found error (API only)
+func IsNotFound(err error) bool {
+	return api.IsNotFound(err)
+}
+
+// IsUnauthorized reports whether err is a Notion unauthorized error (API only)
+func IsUnauthorized(err error) bool {
+	return api.IsUnauthorized(err)
+}
+
+// IsRateLimited reports whether err is a Notion rate_limited error (API only)
+func IsRateLimited(err error) bool {
+	return api.IsRateLimited(err)
+}
+
+// NewClient creates a new Notion client based on the config.
+// The MCP backend reuses the session saved by an earlier command.
 func NewClient(cfg *config.Config) (Client, error) {
+	client, err := newClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if mcpClient, ok := client.(*mcp.Client); ok {
+		// A missing or unreadable session file just means a new session
+		sessionID, _ := config.LoadMCPSession()
+		mcpClient.ResumeSession(sessionID, config.SaveMCPSession)
+	}
+	return client, nil
+}
+
+func newClient(cfg *config.Config) (Client, error) {
 	if cfg.Token == "" {
 		return nil, fmt.Errorf("token is required")
 	}
@@ -32,8 +80,18 @@ func NewClient(cfg *config.Config) (Client, error) {
 	case config.BackendMCP:
 		return mcp.NewClient(cfg.Token)
 	case config.BackendAPI, "":
-		return api.NewClient(cfg.Token), nil
+		return api.NewClient(cfg.Token, cfg.NotionVersion, config.RequestTimeout), nil
 	default:
-		return nil, fmt.Errorf("unknown backend: %s", cfg.Backend)
+		return nil, cfg.Backend.Validate()
 	}
 }
+
+// NewClientWithBackend creates a Notion client for a token and backend without
+// reading configuration files or environment variables. The API backend uses
+// the default Notion-Version and relies on the context for timeouts.
+func NewClientWithBackend(token string, backend config.Backend) (Client, error) {
+	return newClient(&config.Config{
+		Token:   token,
+		Backend: backend,
+	})
+}
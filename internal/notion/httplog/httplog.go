@@ -0,0 +1,75 @@
+package httplog
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/longkey1/gotion/internal/notion/transport"
+)
+
+// Transport logs each HTTP request at debug level: method, URL, status and latency.
+// Headers are never logged, and sensitive query parameters are redacted.
+type Transport struct {
+	Base http.RoundTripper
+}
+
+// NewTransport returns a logging Transport wrapping a transport with the configured TLS settings
+func NewTransport() *Transport {
+	return &Transport{Base: transport.New()}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if !slog.Default().Enabled(req.Context(), slog.LevelDebug) {
+		return base.RoundTrip(req)
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	latency := time.Since(start)
+
+	if err != nil {
+		slog.Debug("http request failed", "method", req.Method, "url", RedactURL(req.URL), "latency", latency, "error", err)
+		return resp, err
+	}
+
+	slog.Debug("http request", "method", req.Method, "url", RedactURL(req.URL), "status", resp.StatusCode, "latency", latency)
+	return resp, nil
+}
+
+// RedactURL returns u as a string with the values of sensitive query parameters replaced
+func RedactURL(u *url.URL) string {
+	if u.RawQuery == "" {
+		return u.String()
+	}
+
+	query := u.Query()
+	for key := range query {
+		if isSensitive(key) {
+			query.Set(key, "REDACTED")
+		}
+	}
+
+	redacted := *u
+	redacted.User = nil
+	redacted.RawQuery = query.Encode()
+	return redacted.String()
+}
+
+func isSensitive(key string) bool {
+	key = strings.ToLower(key)
+	for _, word := range []string{"token", "secret", "code", "password", "key"} {
+		if strings.Contains(key, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -5,20 +5,35 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"strings"
 	"sync/atomic"
 	"time"
 
+	"github.com/longkey1/gotion/internal/gotion"
+	"github.com/longkey1/gotion/internal/notion/httplog"
+	"github.com/longkey1/gotion/internal/notion/retry"
 	"github.com/longkey1/gotion/internal/notion/types"
+	"github.com/longkey1/gotion/internal/version"
 )
 
 const (
 	mcpEndpoint = "https://mcp.notion.com/mcp"
+
+	// maxSSEResumes is the number of times an interrupted SSE stream is resumed
+	maxSSEResumes = 3
 )
 
+// errSSEInterrupted is returned when an SSE stream ends before the response arrives
+var errSSEInterrupted = errors.New("SSE stream ended before response")
+
+// errSessionExpired is returned when the server no longer knows the session ID
+var errSessionExpired = errors.New("MCP session expired")
+
 // Client is a Notion MCP API client
 type Client struct {
 	httpClient  *http.Client
@@ -26,24 +41,45 @@ type Client struct {
 	sessionID   string
 	requestID   atomic.Int64
 	initialized bool
+
+	// saveSession, if set, is called with the ID of each new session
+	saveSession func(sessionID string) error
+
+	// replay, if not nil, holds recorded responses answered instead of contacting the server
+	replay []traceRecord
 }
 
 // NewClient creates a new Notion MCP API client
 func NewClient(token string) (*Client, error) {
 	return &Client{
 		httpClient: &http.Client{
-			Timeout: 60 * time.Second,
+			Timeout:   60 * time.Second,
+			Transport: httplog.NewTransport(),
 		},
 		accessToken: token,
 	}, nil
 }
 
+// ResumeSession reuses sessionID, an initialized session of an earlier
+// command, instead of starting a new one. If the server rejects it, a new
+// session is initialized transparently. save, if not nil, is called with the
+// ID of each new session so later commands can resume it.
+func (c *Client) ResumeSession(sessionID string, save func(sessionID string) error) {
+	c.saveSession = save
+	if sessionID != "" {
+		c.sessionID = sessionID
+		c.initialized = true
+	}
+}
+
 // GetPage retrieves a page by ID using the MCP API
 func (c *Client) GetPage(ctx context.Context, pageID string, opts *types.GetPageOptions) (*types.PageResult, error) {
 	if err := c.ensureInitialized(ctx); err != nil {
 		return nil, err
 	}
 
+	pageID = gotion.ExtractPageID(pageID)
+
 	result, err := c.callTool(ctx, "notion-fetch", map[string]interface{}{
 		"id": pageID,
 	})
@@ -90,10 +126,6 @@ func (c *Client) Search(ctx context.Context, query string, opts *types.SearchOpt
 
 // CreatePage creates a new page using the MCP API
 func (c *Client) CreatePage(ctx context.Context, opts *types.CreatePageOptions) (*types.CreatePageResult, error) {
-	if err := c.ensureInitialized(ctx); err != nil {
-		return nil, err
-	}
-
 	page := map[string]interface{}{
 		"properties": opts.Properties,
 	}
@@ -113,7 +145,7 @@ func (c *Client) CreatePage(ctx context.Context, opts *types.CreatePageOptions)
 		args["parent"] = parent
 	}
 
-	result, err := c.callTool(ctx, "notion-create-pages", args)
+	result, err := c.callWriteTool(ctx, "notion-create-pages", args)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create page: %w", err)
 	}
@@ -126,14 +158,10 @@ func (c *Client) CreatePage(ctx context.Context, opts *types.CreatePageOptions)
 
 // UpdatePage updates an existing page using the MCP API
 func (c *Client) UpdatePage(ctx context.Context, pageID string, opts *types.UpdatePageOptions) (*types.UpdatePageResult, error) {
-	if err := c.ensureInitialized(ctx); err != nil {
-		return nil, err
-	}
-
 	var lastResult *callToolResult
 
 	if opts.Properties != nil && len(opts.Properties) > 0 {
-		result, err := c.callTool(ctx, "notion-update-page", map[string]interface{}{
+		result, err := c.callWriteTool(ctx, "notion-update-page", map[string]interface{}{
 			"page_id":    pageID,
 			"command":    "update_properties",
 			"properties": opts.Properties,
@@ -145,7 +173,7 @@ func (c *Client) UpdatePage(ctx context.Context, pageID string, opts *types.Upda
 	}
 
 	if opts.Content != nil {
-		result, err := c.callTool(ctx, "notion-update-page", map[string]interface{}{
+		result, err := c.callWriteTool(ctx, "notion-update-page", map[string]interface{}{
 			"page_id": pageID,
 			"command": "replace_content",
 			"new_str": *opts.Content,
@@ -166,6 +194,41 @@ func (c *Client) UpdatePage(ctx context.Context, pageID string, opts *types.Upda
 	}, nil
 }
 
+// ArchivePage is not supported with MCP backend
+func (c *Client) ArchivePage(ctx context.Context, pageID string) error {
+	return types.UnsupportedError("delete is not supported with MCP backend, use API backend")
+}
+
+// GetDatabase is not supported with MCP backend
+func (c *Client) GetDatabase(ctx context.Context, databaseID string) (*types.Database, error) {
+	return nil, types.UnsupportedError("db query is not supported with MCP backend, use API backend")
+}
+
+// QueryDatabase is not supported with MCP backend
+func (c *Client) QueryDatabase(ctx context.Context, databaseID string, opts *types.QueryDatabaseOptions) (*types.QueryDatabaseResult, error) {
+	return nil, types.UnsupportedError("db query is not supported with MCP backend, use API backend")
+}
+
+// ListComments is not supported with MCP backend
+func (c *Client) ListComments(ctx context.Context, pageID string) ([]types.Comment, error) {
+	return nil, types.UnsupportedError("comments are not supported with MCP backend, use API backend")
+}
+
+// CreateComment is not supported with MCP backend
+func (c *Client) CreateComment(ctx context.Context, pageID string, text string) (*types.Comment, error) {
+	return nil, types.UnsupportedError("comments are not supported with MCP backend, use API backend")
+}
+
+// AppendBlocks is not supported with MCP backend
+func (c *Client) AppendBlocks(ctx context.Context, blockID string, blocks []map[string]interface{}) error {
+	return types.UnsupportedError("blocks append is not supported with MCP backend, use API backend")
+}
+
+// DeleteBlock is not supported with MCP backend
+func (c *Client) DeleteBlock(ctx context.Context, blockID string) (*types.Block, error) {
+	return nil, types.UnsupportedError("blocks delete is not supported with MCP backend, use API backend")
+}
+
 func (c *Client) ensureInitialized(ctx context.Context) error {
 	if c.initialized {
 		return nil
@@ -196,6 +259,12 @@ func (c *Client) ensureInitialized(ctx context.Context) error {
 	}
 
 	c.initialized = true
+
+	if c.saveSession != nil && c.sessionID != "" {
+		if err := c.saveSession(c.sessionID); err != nil {
+			slog.Debug("failed to save MCP session", "error", err)
+		}
+	}
 	return nil
 }
 
@@ -205,6 +274,53 @@ type callToolResult struct {
 	ContentJSON []byte
 }
 
+// CallTool calls any tool with the given arguments and returns the
+// tool result content as JSON
+func (c *Client) CallTool(ctx context.Context, name string, args map[string]interface{}) ([]byte, error) {
+	if err := c.ensureInitialized(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := c.callTool(ctx, name, args)
+	if err != nil {
+		return nil, err
+	}
+	return result.ContentJSON, nil
+}
+
+// callWriteTool calls a tool that modifies the workspace. In a dry run the
+// call is printed instead, without connecting to the server.
+func (c *Client) callWriteTool(ctx context.Context, name string, args map[string]interface{}) (*callToolResult, error) {
+	if w := types.DryRunWriter(ctx); w != nil {
+		if err := types.WriteDryRun(w, "tools/call "+name, args); err != nil {
+			return nil, err
+		}
+		return &callToolResult{ContentJSON: []byte("[]")}, nil
+	}
+
+	if err := c.ensureInitialized(ctx); err != nil {
+		return nil, err
+	}
+	return c.callTool(ctx, name, args)
+}
+
+// readOnlyTools lists the tools that only read the workspace
+var readOnlyTools = map[string]bool{
+	"notion-fetch":  true,
+	"notion-search": true,
+}
+
+// isWrite reports whether a JSON-RPC request may change the workspace.
+// Tools not known to be read-only are treated as writes.
+func isWrite(method string, params interface{}) bool {
+	if method != "tools/call" {
+		return false
+	}
+	p, _ := params.(map[string]interface{})
+	name, _ := p["name"].(string)
+	return !readOnlyTools[name]
+}
+
 func (c *Client) callTool(ctx context.Context, name string, args map[string]interface{}) (*callToolResult, error) {
 	params := map[string]interface{}{
 		"name":      name,
@@ -244,7 +360,30 @@ func (c *Client) callTool(ctx context.Context, name string, args map[string]inte
 	}, nil
 }
 
+// sendRequest sends a JSON-RPC request. If the session has expired, a new
+// session is initialized and the request is sent once more.
 func (c *Client) sendRequest(ctx context.Context, method string, params interface{}) (*jsonRPCResponse, error) {
+	resp, err := c.sendRequestOnce(ctx, method, params)
+	if !errors.Is(err, errSessionExpired) || method == "initialize" || method == "notifications/initialized" {
+		return resp, err
+	}
+
+	slog.Debug("MCP session expired, initializing a new session", "session_id", c.sessionID)
+	c.sessionID = ""
+	c.initialized = false
+	if err := c.ensureInitialized(ctx); err != nil {
+		return nil, err
+	}
+
+	// The session expired while resuming the response stream, so a write may
+	// already have been applied and is not sent again
+	if errors.Is(err, errSSEInterrupted) && isWrite(method, params) {
+		return nil, fmt.Errorf("%s may have been applied: %w", method, err)
+	}
+	return c.sendRequestOnce(ctx, method, params)
+}
+
+func (c *Client) sendRequestOnce(ctx context.Context, method string, params interface{}) (*jsonRPCResponse, error) {
 	reqID := c.requestID.Add(1)
 
 	req := jsonRPCRequest{
@@ -258,77 +397,201 @@ func (c *Client) sendRequest(ctx context.Context, method string, params interfac
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
+	c.trace("request", method, body)
 
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, mcpEndpoint, bytes.NewReader(body))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	if c.replay != nil {
+		return c.replayResponse(method)
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Accept", "application/json, text/event-stream")
-	httpReq.Header.Set("Authorization", "Bearer "+c.accessToken)
+	policy := retry.DefaultPolicy
+	policy.Write = isWrite(method, params)
+	resp, err := policy.Do(ctx, c.httpClient, func() (*http.Request, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, mcpEndpoint, bytes.NewReader(body))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
 
-	if c.sessionID != "" {
-		httpReq.Header.Set("Mcp-Session-Id", c.sessionID)
-	}
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Accept", "application/json, text/event-stream")
+		httpReq.Header.Set("Authorization", "Bearer "+c.accessToken)
+		httpReq.Header.Set("User-Agent", version.UserAgent())
 
-	resp, err := c.httpClient.Do(httpReq)
+		if c.sessionID != "" {
+			httpReq.Header.Set("Mcp-Session-Id", c.sessionID)
+		}
+		return httpReq, nil
+	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	// The server answers 404 to requests with an unknown or expired session ID
+	if resp.StatusCode == http.StatusNotFound && c.sessionID != "" {
+		return nil, errSessionExpired
+	}
+
 	// Store session ID from response
 	if sessionID := resp.Header.Get("Mcp-Session-Id"); sessionID != "" {
 		c.sessionID = sessionID
 	}
 
-	// Handle SSE response
+	var jsonResp *jsonRPCResponse
 	contentType := resp.Header.Get("Content-Type")
 	if strings.Contains(contentType, "text/event-stream") {
-		return c.parseSSEResponse(resp.Body, reqID)
+		// Handle SSE response
+		jsonResp, err = c.readSSEResponse(ctx, resp.Body, reqID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		// Handle JSON response
+		jsonResp = &jsonRPCResponse{}
+		if err := json.NewDecoder(resp.Body).Decode(jsonResp); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
 	}
 
-	// Handle JSON response
-	var jsonResp jsonRPCResponse
-	if err := json.NewDecoder(resp.Body).Decode(&jsonResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+	if traceFile != "" {
+		if data, err := json.Marshal(jsonResp); err == nil {
+			c.trace("response", method, data)
+		}
 	}
+	return jsonResp, nil
+}
+
+// readSSEResponse reads the response from an SSE stream. If the stream drops
+// after an event ID was seen, it is resumed with Last-Event-ID.
+func (c *Client) readSSEResponse(ctx context.Context, body io.ReadCloser, expectedID int64) (*jsonRPCResponse, error) {
+	// Close whichever stream is current on return; closing the caller's body twice is harmless
+	defer func() { body.Close() }()
+
+	var lastEventID string
+	for resumes := 0; ; resumes++ {
+		resp, err := c.parseSSEResponse(ctx, body, expectedID, &lastEventID)
+		if !errors.Is(err, errSSEInterrupted) || lastEventID == "" || resumes == maxSSEResumes {
+			return resp, err
+		}
+		body.Close()
 
-	return &jsonResp, nil
+		resumed, err := c.resumeSSE(ctx, lastEventID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resume SSE stream after %w: %w", errSSEInterrupted, err)
+		}
+		body = resumed.Body
+	}
 }
 
-func (c *Client) parseSSEResponse(body io.Reader, expectedID int64) (*jsonRPCResponse, error) {
+// resumeSSE reopens the SSE stream after lastEventID. Like other requests it is
+// rate limited and retried, and reports errSessionExpired for an unknown session.
+func (c *Client) resumeSSE(ctx context.Context, lastEventID string) (*http.Response, error) {
+	resp, err := retry.DefaultPolicy.Do(ctx, c.httpClient, func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, mcpEndpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		req.Header.Set("Accept", "text/event-stream")
+		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+		req.Header.Set("Last-Event-ID", lastEventID)
+		req.Header.Set("User-Agent", version.UserAgent())
+		if c.sessionID != "" {
+			req.Header.Set("Mcp-Session-Id", c.sessionID)
+		}
+		return req, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode == http.StatusNotFound && c.sessionID != "" {
+		resp.Body.Close()
+		return nil, errSessionExpired
+	}
+
+	if resp.StatusCode != http.StatusOK || !strings.Contains(resp.Header.Get("Content-Type"), "text/event-stream") {
+		resp.Body.Close()
+		return nil, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+
+	return resp, nil
+}
+
+// parseSSEResponse reads events until the JSON-RPC response with expectedID arrives.
+// lastEventID is updated as "id:" fields are read. If ctx is done first, body
+// is closed to unblock the read and ctx.Err() is returned.
+func (c *Client) parseSSEResponse(ctx context.Context, body io.ReadCloser, expectedID int64, lastEventID *string) (*jsonRPCResponse, error) {
+	stop := context.AfterFunc(ctx, func() { body.Close() })
+	defer stop()
+
 	scanner := bufio.NewScanner(body)
 	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
-	var dataBuffer strings.Builder
+	var dataLines []string
+	var eventType string
 
 	for scanner.Scan() {
 		line := scanner.Text()
 
-		if strings.HasPrefix(line, "data: ") {
-			data := strings.TrimPrefix(line, "data: ")
-			dataBuffer.WriteString(data)
-		} else if line == "" && dataBuffer.Len() > 0 {
+		// A blank line dispatches the event
+		if line == "" {
+			data := strings.Join(dataLines, "\n")
+			isMessage := eventType == "" || eventType == "message"
+			dataLines = dataLines[:0]
+			eventType = ""
+
+			if data == "" || !isMessage {
+				continue
+			}
+
 			var resp jsonRPCResponse
-			if err := json.Unmarshal([]byte(dataBuffer.String()), &resp); err != nil {
-				dataBuffer.Reset()
+			if err := json.Unmarshal([]byte(data), &resp); err != nil {
+				c.trace("event", "", []byte(data))
+				continue
+			}
+
+			// Skip server-initiated notifications and requests, whose
+			// IDs come from the server's own sequence and may collide
+			if resp.Method != "" {
+				c.trace("event", resp.Method, []byte(data))
 				continue
 			}
 
 			if resp.ID == expectedID {
 				return &resp, nil
 			}
+			c.trace("event", "", []byte(data))
+			continue
+		}
 
-			dataBuffer.Reset()
+		// Lines starting with a colon are comments
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "data":
+			dataLines = append(dataLines, value)
+		case "event":
+			eventType = value
+		case "id":
+			// IDs containing NULL must be ignored per the SSE spec
+			if !strings.Contains(value, "\x00") {
+				*lastEventID = value
+			}
 		}
 	}
 
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("failed to read SSE response: %w", err)
+		return nil, fmt.Errorf("%w: %v", errSSEInterrupted, err)
 	}
 
-	return nil, fmt.Errorf("no response received for request ID %d", expectedID)
+	return nil, fmt.Errorf("%w: no response received for request ID %d", errSSEInterrupted, expectedID)
 }
 
 // Internal types
@@ -342,6 +605,7 @@ type jsonRPCRequest struct {
 
 type jsonRPCResponse struct {
 	JSONRPC string          `json:"jsonrpc"`
+	Method  string          `json:"method,omitempty"` // Set on server-initiated messages only
 	Result  json.RawMessage `json:"result,omitempty"`
 	Error   json.RawMessage `json:"error,omitempty"`
 	ID      int64           `json:"id"`
@@ -392,14 +656,44 @@ type mcpTextResponse struct {
 	Text     string                 `json:"text,omitempty"`
 }
 
-// FormatPage formats a page result as JSON string
+// pageJSON is the JSON output of a page fetched with the MCP backend
+type pageJSON struct {
+	ID       string                 `json:"id"`
+	Title    string                 `json:"title"`
+	URL      string                 `json:"url"`
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+	Text     string                 `json:"text"`
+}
+
+// FormatPage formats a page result as a JSON object built from the MCP response
 func (c *Client) FormatPage(result *types.PageResult) (string, error) {
-	return string(result.RawJSON), nil
+	page := pageJSON{
+		ID:    result.ID,
+		Title: result.Title,
+		URL:   result.URL,
+		Text:  result.Content,
+	}
+
+	var contents []toolContent
+	if err := json.Unmarshal(result.RawJSON, &contents); err == nil {
+		if resp := findTextResponse(contents); resp != nil {
+			page.Metadata = resp.Metadata
+		}
+	}
+
+	output, err := json.MarshalIndent(page, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal page: %w", err)
+	}
+	return string(output) + "\n", nil
 }
 
-// FormatSearch formats a search result as JSON string
+// FormatSearch formats a search result as a types.SearchList envelope with
+// the tool response content, as MCP search has no structured results
 func (c *Client) FormatSearch(result *types.SearchResult) (string, error) {
-	return string(result.RawJSON), nil
+	list := types.NewSearchList(result)
+	list.Content = json.RawMessage(result.RawJSON)
+	return list.Format()
 }
 
 // extractPageMetadata extracts title, url, and markdown content from MCP tool result content
@@ -408,18 +702,30 @@ func extractPageMetadata(result *toolResult) (title, url, content string) {
 		return "", "", ""
 	}
 
+	if resp := findTextResponse(result.Content); resp != nil {
+		return resp.Title, resp.URL, resp.Text
+	}
+
+	// If not JSON, treat the first text content as plain markdown
 	for _, c := range result.Content {
 		if c.Type == "text" {
-			var resp mcpTextResponse
-			if err := json.Unmarshal([]byte(c.Text), &resp); err == nil {
-				return resp.Title, resp.URL, resp.Text
-			}
-			// If not JSON, treat as plain markdown content
-			if content == "" {
-				content = c.Text
-			}
+			return "", "", c.Text
 		}
 	}
 
-	return title, url, content
+	return "", "", ""
+}
+
+// findTextResponse returns the first text content that parses as an mcpTextResponse
+func findTextResponse(contents []toolContent) *mcpTextResponse {
+	for _, c := range contents {
+		if c.Type != "text" {
+			continue
+		}
+		var resp mcpTextResponse
+		if err := json.Unmarshal([]byte(c.Text), &resp); err == nil {
+			return &resp
+		}
+	}
+	return nil
 }
@@ -0,0 +1,408 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// rewriteTransport sends requests for mcpEndpoint to a test server instead
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+// newTestClient returns a client whose requests are served by handler
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	target, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client, err := NewClient("test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	client.httpClient.Transport = &rewriteTransport{target: target}
+	return client
+}
+
+// sseBody returns an SSE stream body from lines joined by newlines
+func sseBody(lines ...string) io.ReadCloser {
+	return io.NopCloser(strings.NewReader(strings.Join(lines, "\n") + "\n"))
+}
+
+func TestParseSSEResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       io.ReadCloser
+		wantResult string
+		wantEvent  string
+	}{
+		{
+			name: "single event",
+			body: sseBody(
+				`data: {"jsonrpc":"2.0","id":7,"result":{"ok":true}}`,
+				``,
+			),
+			wantResult: `{"ok":true}`,
+		},
+		{
+			name: "notification before the result",
+			body: sseBody(
+				`event: message`,
+				`data: {"jsonrpc":"2.0","method":"notifications/progress","params":{"progress":1}}`,
+				``,
+				`event: message`,
+				`data: {"jsonrpc":"2.0","id":7,"result":{"ok":true}}`,
+				``,
+			),
+			wantResult: `{"ok":true}`,
+		},
+		{
+			name: "multi-line data",
+			body: sseBody(
+				`data: {"jsonrpc":"2.0",`,
+				`data: "id":7,`,
+				`data: "result":{"ok":true}}`,
+				``,
+			),
+			wantResult: `{"ok":true}`,
+		},
+		{
+			name: "server request with a colliding ID",
+			body: sseBody(
+				`data: {"jsonrpc":"2.0","id":7,"method":"sampling/createMessage","params":{}}`,
+				``,
+				`data: {"jsonrpc":"2.0","id":7,"result":{"ok":true}}`,
+				``,
+			),
+			wantResult: `{"ok":true}`,
+		},
+		{
+			name: "other event types and responses",
+			body: sseBody(
+				`: keep-alive comment`,
+				`event: ping`,
+				`data: {"jsonrpc":"2.0","id":7,"result":{"ping":true}}`,
+				``,
+				`data: {"jsonrpc":"2.0","id":6,"result":{"stale":true}}`,
+				``,
+				`data: not json`,
+				``,
+				`data: {"jsonrpc":"2.0","id":7,"result":{"ok":true}}`,
+				``,
+			),
+			wantResult: `{"ok":true}`,
+		},
+		{
+			name: "event IDs",
+			body: sseBody(
+				`id: 1`,
+				`data: {"jsonrpc":"2.0","method":"notifications/message","params":{}}`,
+				``,
+				`id: 2`,
+				`data: {"jsonrpc":"2.0","id":7,"result":{"ok":true}}`,
+				``,
+			),
+			wantResult: `{"ok":true}`,
+			wantEvent:  "2",
+		},
+		{
+			name: "ID with NULL is ignored",
+			body: sseBody(
+				`id: 1`,
+				`data: {"jsonrpc":"2.0","method":"notifications/message","params":{}}`,
+				``,
+				"id: 2\x00",
+				`data: {"jsonrpc":"2.0","id":7,"result":{"ok":true}}`,
+				``,
+			),
+			wantResult: `{"ok":true}`,
+			wantEvent:  "1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{}
+			var lastEventID string
+			resp, err := c.parseSSEResponse(context.Background(), tt.body, 7, &lastEventID)
+			if err != nil {
+				t.Fatalf("parseSSEResponse: %v", err)
+			}
+			if string(resp.Result) != tt.wantResult {
+				t.Errorf("result = %s, want %s", resp.Result, tt.wantResult)
+			}
+			if lastEventID != tt.wantEvent {
+				t.Errorf("lastEventID = %q, want %q", lastEventID, tt.wantEvent)
+			}
+		})
+	}
+}
+
+func TestParseSSEResponseInterrupted(t *testing.T) {
+	c := &Client{}
+	var lastEventID string
+	body := sseBody(
+		`id: 3`,
+		`data: {"jsonrpc":"2.0","method":"notifications/progress","params":{}}`,
+		``,
+		`data: {"jsonrpc":"2.0","id":7,`,
+	)
+
+	_, err := c.parseSSEResponse(context.Background(), body, 7, &lastEventID)
+	if !errors.Is(err, errSSEInterrupted) {
+		t.Fatalf("error = %v, want %v", err, errSSEInterrupted)
+	}
+	if lastEventID != "3" {
+		t.Errorf("lastEventID = %q, want %q", lastEventID, "3")
+	}
+}
+
+func TestReadSSEResponseResumes(t *testing.T) {
+	var resumedAfter string
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "unexpected method", http.StatusMethodNotAllowed)
+			return
+		}
+		resumedAfter = r.Header.Get("Last-Event-ID")
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, "id: 3\ndata: {\"jsonrpc\":\"2.0\",\"id\":7,\"result\":{\"ok\":true}}\n\n")
+	}))
+
+	// The first stream drops after event 2, before the response
+	body := sseBody(
+		`id: 1`,
+		`data: {"jsonrpc":"2.0","method":"notifications/progress","params":{}}`,
+		``,
+		`id: 2`,
+		`data: {"jsonrpc":"2.0","method":"notifications/progress","params":{}}`,
+		``,
+	)
+
+	resp, err := c.readSSEResponse(context.Background(), body, 7)
+	if err != nil {
+		t.Fatalf("readSSEResponse: %v", err)
+	}
+	if string(resp.Result) != `{"ok":true}` {
+		t.Errorf("result = %s, want %s", resp.Result, `{"ok":true}`)
+	}
+	if resumedAfter != "2" {
+		t.Errorf("resumed with Last-Event-ID %q, want %q", resumedAfter, "2")
+	}
+}
+
+func TestReadSSEResponseWithoutEventIDs(t *testing.T) {
+	resumed := false
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resumed = true
+	}))
+
+	// Without an event ID there is nothing to resume from
+	body := sseBody(
+		`data: {"jsonrpc":"2.0","method":"notifications/progress","params":{}}`,
+		``,
+	)
+
+	_, err := c.readSSEResponse(context.Background(), body, 7)
+	if !errors.Is(err, errSSEInterrupted) {
+		t.Fatalf("error = %v, want %v", err, errSSEInterrupted)
+	}
+	if resumed {
+		t.Error("stream without event IDs was resumed")
+	}
+}
+
+func TestSendRequestReinitializesExpiredSession(t *testing.T) {
+	var methods []string
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		methods = append(methods, req.Method)
+
+		session := r.Header.Get("Mcp-Session-Id")
+		switch {
+		case req.Method == "initialize":
+			w.Header().Set("Mcp-Session-Id", "fresh")
+		case session != "fresh":
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  map[string]string{"session": session},
+		})
+	}))
+
+	var saved string
+	c.ResumeSession("stale", func(sessionID string) error {
+		saved = sessionID
+		return nil
+	})
+
+	resp, err := c.sendRequest(context.Background(), "tools/list", nil)
+	if err != nil {
+		t.Fatalf("sendRequest: %v", err)
+	}
+	if string(resp.Result) != `{"session":"fresh"}` {
+		t.Errorf("result = %s, want the request answered in the new session", resp.Result)
+	}
+
+	want := []string{"tools/list", "initialize", "notifications/initialized", "tools/list"}
+	if strings.Join(methods, ",") != strings.Join(want, ",") {
+		t.Errorf("requests = %v, want %v", methods, want)
+	}
+	if saved != "fresh" {
+		t.Errorf("saved session = %q, want %q", saved, "fresh")
+	}
+}
+
+// closeTracker records whether the body it wraps was closed
+type closeTracker struct {
+	io.ReadCloser
+	closed atomic.Bool
+}
+
+func (b *closeTracker) Close() error {
+	b.closed.Store(true)
+	return b.ReadCloser.Close()
+}
+
+func TestReadSSEResponseClosesInterruptedStreams(t *testing.T) {
+	first := &closeTracker{ReadCloser: sseBody(
+		`id: 1`,
+		`data: {"jsonrpc":"2.0","method":"notifications/progress","params":{}}`,
+		``,
+	)}
+
+	var resumes int
+	var firstClosedOnResume bool
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resumes++
+		w.Header().Set("Content-Type", "text/event-stream")
+		if resumes == 1 {
+			firstClosedOnResume = first.closed.Load()
+			// Drops again after another event
+			io.WriteString(w, "id: 2\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\",\"params\":{}}\n\n")
+			return
+		}
+		io.WriteString(w, "id: 3\ndata: {\"jsonrpc\":\"2.0\",\"id\":7,\"result\":{\"ok\":true}}\n\n")
+	}))
+
+	resp, err := c.readSSEResponse(context.Background(), first, 7)
+	if err != nil {
+		t.Fatalf("readSSEResponse: %v", err)
+	}
+	if string(resp.Result) != `{"ok":true}` {
+		t.Errorf("result = %s, want %s", resp.Result, `{"ok":true}`)
+	}
+	if resumes != 2 {
+		t.Errorf("resumed %d times, want 2", resumes)
+	}
+	if !firstClosedOnResume {
+		t.Error("interrupted stream was still open when resuming")
+	}
+}
+
+// expiringResumeHandler answers the first POST in session "old" with a stream
+// that drops, rejects resuming it as an expired session and answers requests
+// in the new session "fresh". Each request is recorded as its method, or GET.
+func expiringResumeHandler(requests *[]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		session := r.Header.Get("Mcp-Session-Id")
+		if r.Method == http.MethodGet {
+			*requests = append(*requests, "GET")
+			if session != "fresh" {
+				http.Error(w, "session not found", http.StatusNotFound)
+			}
+			return
+		}
+
+		var req jsonRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		*requests = append(*requests, req.Method)
+
+		switch {
+		case req.Method == "initialize":
+			w.Header().Set("Mcp-Session-Id", "fresh")
+		case session == "old":
+			w.Header().Set("Content-Type", "text/event-stream")
+			io.WriteString(w, "id: 1\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"notifications/progress\",\"params\":{}}\n\n")
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  map[string]string{"session": session},
+		})
+	})
+}
+
+func TestResumeOnExpiredSessionReinitializes(t *testing.T) {
+	var requests []string
+	c := newTestClient(t, expiringResumeHandler(&requests))
+	c.ResumeSession("old", nil)
+
+	resp, err := c.sendRequest(context.Background(), "tools/list", nil)
+	if err != nil {
+		t.Fatalf("sendRequest: %v", err)
+	}
+	if string(resp.Result) != `{"session":"fresh"}` {
+		t.Errorf("result = %s, want the request answered in the new session", resp.Result)
+	}
+
+	want := []string{"tools/list", "GET", "initialize", "notifications/initialized", "tools/list"}
+	if strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+}
+
+func TestResumeOnExpiredSessionDoesNotResendWrites(t *testing.T) {
+	var requests []string
+	c := newTestClient(t, expiringResumeHandler(&requests))
+	c.ResumeSession("old", nil)
+
+	params := map[string]interface{}{"name": "notion-create-pages", "arguments": map[string]interface{}{}}
+	if _, err := c.sendRequest(context.Background(), "tools/call", params); err == nil {
+		t.Fatal("sendRequest succeeded, want an error for the lost response")
+	}
+
+	want := []string{"tools/call", "GET", "initialize", "notifications/initialized"}
+	if strings.Join(requests, ",") != strings.Join(want, ",") {
+		t.Errorf("requests = %v, want %v", requests, want)
+	}
+	if c.sessionID != "fresh" {
+		t.Errorf("session = %q, want the new session %q", c.sessionID, "fresh")
+	}
+}
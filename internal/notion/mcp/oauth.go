@@ -12,6 +12,9 @@ import (
 	"net/url"
 	"strings"
 	"time"
+
+	"github.com/longkey1/gotion/internal/notion/httplog"
+	"github.com/longkey1/gotion/internal/version"
 )
 
 const (
@@ -97,7 +100,7 @@ func NewOAuthClient(callbackURL string) *OAuthClient {
 		callbackURL = defaultCallbackURL
 	}
 	return &OAuthClient{
-		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		httpClient:   &http.Client{Timeout: 30 * time.Second, Transport: httplog.NewTransport()},
 		mcpServerURL: serverURL,
 		callbackURL:  callbackURL,
 	}
@@ -111,6 +114,7 @@ func (c *OAuthClient) DiscoverEndpoints(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to create protected resource request: %w", err)
 	}
+	req.Header.Set("User-Agent", version.UserAgent())
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -141,6 +145,7 @@ func (c *OAuthClient) DiscoverEndpoints(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("failed to create auth server request: %w", err)
 	}
+	req.Header.Set("User-Agent", version.UserAgent())
 
 	resp, err = c.httpClient.Do(req)
 	if err != nil {
@@ -195,6 +200,7 @@ func (c *OAuthClient) RegisterClient(ctx context.Context) error {
 		return fmt.Errorf("failed to create registration request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", version.UserAgent())
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
@@ -287,42 +293,7 @@ func (c *OAuthClient) ExchangeCode(ctx context.Context, code string) (*OAuthToke
 	data.Set("client_id", c.clientReg.ClientID)
 	data.Set("code_verifier", c.pkce.CodeVerifier)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.authServer.TokenEndpoint, strings.NewReader(data.Encode()))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create token request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to exchange code: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to exchange code: HTTP %d: %s", resp.StatusCode, string(body))
-	}
-
-	var token OAuthToken
-	if err := json.Unmarshal(body, &token); err != nil {
-		return nil, fmt.Errorf("failed to decode token response: %w", err)
-	}
-
-	if token.AccessToken == "" {
-		return nil, fmt.Errorf("no access_token in token response")
-	}
-
-	// Calculate expires_at if expires_in is provided
-	if token.ExpiresIn > 0 {
-		token.ExpiresAt = time.Now().Unix() + int64(token.ExpiresIn)
-	}
-
-	return &token, nil
+	return c.requestToken(ctx, data, "exchange code")
 }
 
 // GetClientID returns the registered client ID
@@ -341,7 +312,7 @@ func (c *OAuthClient) GetCallbackURL() string {
 // RefreshToken refreshes an access token using a refresh token
 func RefreshToken(ctx context.Context, clientID, refreshToken string) (*OAuthToken, error) {
 	client := &OAuthClient{
-		httpClient:   &http.Client{Timeout: 30 * time.Second},
+		httpClient:   &http.Client{Timeout: 30 * time.Second, Transport: httplog.NewTransport()},
 		mcpServerURL: serverURL,
 	}
 
@@ -355,15 +326,22 @@ func RefreshToken(ctx context.Context, clientID, refreshToken string) (*OAuthTok
 	data.Set("refresh_token", refreshToken)
 	data.Set("client_id", clientID)
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, client.authServer.TokenEndpoint, strings.NewReader(data.Encode()))
+	return client.requestToken(ctx, data, "refresh token")
+}
+
+// requestToken posts a token request to the token endpoint and decodes the response.
+// action describes the request in error messages (e.g. "refresh token").
+func (c *OAuthClient) requestToken(ctx context.Context, data url.Values, action string) (*OAuthToken, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.authServer.TokenEndpoint, strings.NewReader(data.Encode()))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create refresh request: %w", err)
+		return nil, fmt.Errorf("failed to create %s request: %w", action, err)
 	}
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", version.UserAgent())
 
-	resp, err := client.httpClient.Do(req)
+	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to refresh token: %w", err)
+		return nil, fmt.Errorf("failed to %s: %w", action, err)
 	}
 	defer resp.Body.Close()
 
@@ -373,7 +351,7 @@ func RefreshToken(ctx context.Context, clientID, refreshToken string) (*OAuthTok
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to refresh token: HTTP %d: %s", resp.StatusCode, string(body))
+		return nil, fmt.Errorf("failed to %s: HTTP %d: %s", action, resp.StatusCode, string(body))
 	}
 
 	var token OAuthToken
@@ -382,7 +360,7 @@ func RefreshToken(ctx context.Context, clientID, refreshToken string) (*OAuthTok
 	}
 
 	if token.AccessToken == "" {
-		return nil, fmt.Errorf("no access_token in refresh response")
+		return nil, fmt.Errorf("no access_token in %s response", action)
 	}
 
 	// Calculate expires_at if expires_in is provided
@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TraceEnv names a file to which every JSON-RPC request and response is
+// appended, for reporting bugs against the Notion MCP server
+const TraceEnv = "GOTION_MCP_TRACE"
+
+var (
+	// traceFile is the file set by ConfigureTrace; empty disables tracing
+	traceFile string
+	traceMu   sync.Mutex
+)
+
+// ConfigureTrace sets the file that JSON-RPC messages are appended to.
+// An empty path disables tracing.
+func ConfigureTrace(path string) {
+	traceFile = path
+}
+
+// traceRecord is a single message in a trace file. Records are written as
+// indented JSON objects one after another.
+type traceRecord struct {
+	Time    string          `json:"time"`
+	Type    string          `json:"type"` // "request", "response", or "event" for other SSE messages
+	Method  string          `json:"method,omitempty"`
+	Message json.RawMessage `json:"message"`
+}
+
+// trace appends a message to the trace file, with the access token redacted.
+// Tracing is best-effort: failures are logged and otherwise ignored.
+func (c *Client) trace(recordType, method string, message []byte) {
+	if traceFile == "" {
+		return
+	}
+
+	if c.accessToken != "" {
+		message = bytes.ReplaceAll(message, []byte(c.accessToken), []byte("[REDACTED]"))
+	}
+	if !json.Valid(message) {
+		message, _ = json.Marshal(string(message))
+	}
+
+	data, err := json.MarshalIndent(traceRecord{
+		Time:    time.Now().Format(time.RFC3339Nano),
+		Type:    recordType,
+		Method:  method,
+		Message: message,
+	}, "", "  ")
+	if err != nil {
+		slog.Debug("failed to marshal MCP trace record", "error", err)
+		return
+	}
+
+	traceMu.Lock()
+	defer traceMu.Unlock()
+
+	f, err := os.OpenFile(traceFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		slog.Debug("failed to open MCP trace file", "error", err)
+		return
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		slog.Debug("failed to write MCP trace file", "error", err)
+	}
+}
+
+// Replay makes the client answer requests from a trace file written with
+// GOTION_MCP_TRACE instead of contacting the server, to reproduce a session
+// offline. Each request takes the next recorded response to the same method.
+func (c *Client) Replay(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open trace file: %w", err)
+	}
+	defer f.Close()
+
+	c.replay = []traceRecord{}
+	dec := json.NewDecoder(f)
+	for {
+		var record traceRecord
+		if err := dec.Decode(&record); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return fmt.Errorf("failed to read trace file: %w", err)
+		}
+		if record.Type == "response" {
+			c.replay = append(c.replay, record)
+		}
+	}
+	return nil
+}
+
+// replayResponse returns the next recorded response to method
+func (c *Client) replayResponse(method string) (*jsonRPCResponse, error) {
+	for i, record := range c.replay {
+		if record.Method != method {
+			continue
+		}
+		c.replay = append(c.replay[:i], c.replay[i+1:]...)
+
+		var resp jsonRPCResponse
+		if err := json.Unmarshal(record.Message, &resp); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal recorded response: %w", err)
+		}
+		return &resp, nil
+	}
+
+	// Notifications have no response to record
+	if strings.HasPrefix(method, "notifications/") {
+		return &jsonRPCResponse{}, nil
+	}
+	return nil, fmt.Errorf("no recorded response for %s", method)
+}
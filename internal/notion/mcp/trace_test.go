@@ -0,0 +1,75 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTraceAndReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.json")
+	ConfigureTrace(path)
+	t.Cleanup(func() { ConfigureTrace("") })
+
+	// The server echoes the token, which must not reach the trace file
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req jsonRPCRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  map[string]string{"method": req.Method, "auth": r.Header.Get("Authorization")},
+		})
+	}))
+
+	recorded, err := c.sendRequest(context.Background(), "tools/list", nil)
+	if err != nil {
+		t.Fatalf("sendRequest: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("trace file: %v", err)
+	}
+	if strings.Contains(string(data), "test-token") {
+		t.Errorf("trace file contains the access token:\n%s", data)
+	}
+	if !strings.Contains(string(data), `"type": "request"`) || !strings.Contains(string(data), `"type": "response"`) {
+		t.Errorf("trace file lacks the request or response:\n%s", data)
+	}
+
+	// Replaying needs no server and answers with the recorded, redacted response
+	ConfigureTrace("")
+	replayer, err := NewClient("test-token")
+	if err != nil {
+		t.Fatal(err)
+	}
+	replayer.httpClient = nil
+	if err := replayer.Replay(path); err != nil {
+		t.Fatalf("Replay: %v", err)
+	}
+
+	replayed, err := replayer.sendRequest(context.Background(), "tools/list", nil)
+	if err != nil {
+		t.Fatalf("replayed sendRequest: %v", err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(replayed.Result, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["method"] != "tools/list" || got["auth"] != "Bearer [REDACTED]" {
+		t.Errorf("replayed result = %s, want the recorded %s with the token redacted", replayed.Result, recorded.Result)
+	}
+
+	if _, err := replayer.sendRequest(context.Background(), "tools/call", nil); err == nil {
+		t.Error("replaying an unrecorded method succeeded, want an error")
+	}
+}
@@ -0,0 +1,83 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/longkey1/gotion/internal/notion/metrics"
+)
+
+// Limiter is a token-bucket rate limiter. The bucket holds up to burst
+// tokens and refills at rps tokens per second; each request takes one token.
+// A nil Limiter does not limit.
+type Limiter struct {
+	mu     sync.Mutex
+	rps    float64
+	burst  int
+	tokens float64
+	last   time.Time
+}
+
+// Default is the limiter applied to Notion API requests; nil means unlimited
+var Default *Limiter
+
+// New creates a limiter allowing rps requests per second on average and up to
+// burst requests at once. A burst of 0 defaults to rps rounded up.
+func New(rps float64, burst int) (*Limiter, error) {
+	if rps <= 0 {
+		return nil, fmt.Errorf("rate limit must be positive: %v", rps)
+	}
+	if burst < 0 {
+		return nil, fmt.Errorf("rate limit burst must be positive: %d", burst)
+	}
+	if burst == 0 {
+		burst = int(math.Ceil(rps))
+	}
+
+	return &Limiter{
+		rps:    rps,
+		burst:  burst,
+		tokens: float64(burst),
+		last:   time.Now(),
+	}, nil
+}
+
+// Wait blocks until a request may proceed or ctx is done
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	delay := l.reserve()
+	if delay <= 0 {
+		return nil
+	}
+	metrics.RateLimitWaits.Inc()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(delay):
+		return nil
+	}
+}
+
+// reserve takes a token and returns how long to wait until it is available
+func (l *Limiter) reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.tokens = math.Min(float64(l.burst), l.tokens+now.Sub(l.last).Seconds()*l.rps)
+	l.last = now
+
+	// Tokens may go negative: waiters queue up behind each other
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rps * float64(time.Second))
+}
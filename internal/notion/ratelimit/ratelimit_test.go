@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name      string
+		rps       float64
+		burst     int
+		wantBurst int
+		wantErr   bool
+	}{
+		{name: "explicit burst", rps: 3, burst: 5, wantBurst: 5},
+		{name: "default burst", rps: 3, wantBurst: 3},
+		{name: "default burst rounds up", rps: 0.5, wantBurst: 1},
+		{name: "zero rate", rps: 0, wantErr: true},
+		{name: "negative burst", rps: 1, burst: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := New(tt.rps, tt.burst)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("New error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && l.burst != tt.wantBurst {
+				t.Errorf("burst = %d, want %d", l.burst, tt.wantBurst)
+			}
+		})
+	}
+}
+
+func TestBurst(t *testing.T) {
+	// At one request per second, the refill during the test is negligible
+	l, err := New(1, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if delay := l.reserve(); delay != 0 {
+			t.Fatalf("request %d within the burst waited %s", i+1, delay)
+		}
+	}
+
+	// Later requests queue up one interval apart
+	for i, want := range []time.Duration{time.Second, 2 * time.Second} {
+		delay := l.reserve()
+		if delay < want-50*time.Millisecond || delay > want {
+			t.Errorf("request %d waited %s, want about %s", i+4, delay, want)
+		}
+	}
+}
+
+func TestBurstRefills(t *testing.T) {
+	l, err := New(100, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l.reserve()
+	l.reserve()
+	if delay := l.reserve(); delay <= 0 {
+		t.Fatal("request beyond the burst did not wait")
+	}
+
+	// After a pause the bucket refills, but never beyond the burst
+	l.last = l.last.Add(-time.Second)
+	l.tokens = 0
+	for i := 0; i < 2; i++ {
+		if delay := l.reserve(); delay != 0 {
+			t.Errorf("request %d after refill waited %s", i+1, delay)
+		}
+	}
+	if delay := l.reserve(); delay <= 0 {
+		t.Error("refill exceeded the burst")
+	}
+}
+
+func TestWait(t *testing.T) {
+	var nilLimiter *Limiter
+	if err := nilLimiter.Wait(context.Background()); err != nil {
+		t.Errorf("nil limiter Wait = %v, want nil", err)
+	}
+
+	l, err := New(1, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait within the burst = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait with canceled context = %v, want %v", err, context.Canceled)
+	}
+}
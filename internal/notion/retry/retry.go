@@ -0,0 +1,187 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/longkey1/gotion/internal/notion/metrics"
+	"github.com/longkey1/gotion/internal/notion/ratelimit"
+)
+
+const (
+	// DefaultMaxAttempts is the number of attempts made by Do, including the first
+	DefaultMaxAttempts = 3
+	// DefaultDelay is the base backoff before the first retry; it doubles for
+	// each later retry and up to half of it is added as jitter
+	DefaultDelay = 500 * time.Millisecond
+)
+
+// Policy decides whether a failed request may be retried and how long to wait
+type Policy struct {
+	// RetryConflict allows retrying 409 Conflict responses
+	RetryConflict bool
+
+	// Write marks requests that change data. The server may already have
+	// applied a write that failed with a 5xx or a dropped connection, so
+	// writes are retried only on 429 and on errors raised before sending.
+	Write bool
+
+	// MaxAttempts overrides DefaultMaxAttempts when non-zero
+	MaxAttempts int
+	// BaseDelay overrides DefaultDelay when non-zero
+	BaseDelay time.Duration
+}
+
+// DefaultPolicy is the retry policy used by the Notion clients
+var DefaultPolicy = Policy{}
+
+// IsRetryable reports whether a request that failed with err, or completed
+// with statusCode, may be retried:
+//   - context cancellation and deadlines are never retryable
+//   - network timeouts, connection resets and unexpected EOFs are retryable
+//   - 429, 502, 503 and 504 responses are retryable
+//   - 409 is retryable only if RetryConflict is set
+//   - any other status is not retryable
+//
+// For a Write, only 429, 409 with RetryConflict and failures to connect are retryable.
+func (p Policy) IsRetryable(err error, statusCode int) bool {
+	if err != nil {
+		if p.Write {
+			return isDialError(err)
+		}
+		return isRetryableError(err)
+	}
+
+	switch {
+	case statusCode == http.StatusTooManyRequests:
+		return true
+	case statusCode == http.StatusConflict:
+		return p.RetryConflict
+	case statusCode == http.StatusBadGateway, statusCode == http.StatusServiceUnavailable, statusCode == http.StatusGatewayTimeout:
+		return !p.Write
+	default:
+		return false
+	}
+}
+
+func isRetryableError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	return false
+}
+
+// isDialError reports whether err occurred while connecting, before any of
+// the request was sent
+func isDialError(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// Do sends a request built by newRequest, retrying according to the policy.
+// newRequest is called for each attempt so request bodies can be re-read.
+// 429 responses wait for their Retry-After header when present; other retries
+// use exponential backoff with jitter. Non-retryable responses return immediately.
+// The last response or error is returned; the caller must close the response body.
+func (p Policy) Do(ctx context.Context, client *http.Client, newRequest func() (*http.Request, error)) (*http.Response, error) {
+	maxAttempts := p.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = DefaultMaxAttempts
+	}
+	delay := p.BaseDelay
+	if delay == 0 {
+		delay = DefaultDelay
+	}
+
+	for attempt := 1; ; attempt++ {
+		if err := ratelimit.Default.Wait(ctx); err != nil {
+			return nil, err
+		}
+
+		req, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+
+		start := time.Now()
+		resp, err := client.Do(req)
+
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		observe(req, statusCode, time.Since(start))
+
+		if attempt >= maxAttempts || !p.IsRetryable(err, statusCode) {
+			return resp, err
+		}
+
+		wait := withJitter(delay)
+		if statusCode == http.StatusTooManyRequests {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+				wait = retryAfter
+			}
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(wait):
+		}
+		delay *= 2
+	}
+}
+
+// withJitter adds a random duration of up to half of d
+func withJitter(d time.Duration) time.Duration {
+	if d <= 1 {
+		return d
+	}
+	return d + rand.N(d/2)
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds
+func parseRetryAfter(value string) (time.Duration, bool) {
+	seconds, err := strconv.Atoi(value)
+	if err != nil || seconds < 0 {
+		return 0, false
+	}
+	return time.Duration(seconds) * time.Second, true
+}
+
+// observe records the latency and outcome of a request in the metrics
+func observe(req *http.Request, statusCode int, latency time.Duration) {
+	code := "error"
+	if statusCode != 0 {
+		code = strconv.Itoa(statusCode)
+	}
+	metrics.NotionRequestDuration.Observe(latency.Seconds(), req.URL.Host, code)
+	if statusCode == http.StatusTooManyRequests {
+		metrics.NotionRateLimited.Inc(req.URL.Host)
+	}
+}
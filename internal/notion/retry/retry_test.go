@@ -0,0 +1,244 @@
+package retry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// timeoutError is a net.Error that reports a timeout
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestIsRetryable(t *testing.T) {
+	connReset := &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+	connRefused := &net.OpError{Op: "dial", Net: "tcp", Err: syscall.ECONNREFUSED}
+
+	tests := []struct {
+		name       string
+		policy     Policy
+		err        error
+		statusCode int
+		want       bool
+	}{
+		{name: "200", statusCode: http.StatusOK, want: false},
+		{name: "429", statusCode: http.StatusTooManyRequests, want: true},
+		{name: "502", statusCode: http.StatusBadGateway, want: true},
+		{name: "503", statusCode: http.StatusServiceUnavailable, want: true},
+		{name: "504", statusCode: http.StatusGatewayTimeout, want: true},
+		{name: "500", statusCode: http.StatusInternalServerError, want: false},
+		{name: "400", statusCode: http.StatusBadRequest, want: false},
+		{name: "401", statusCode: http.StatusUnauthorized, want: false},
+		{name: "404", statusCode: http.StatusNotFound, want: false},
+		{name: "409", statusCode: http.StatusConflict, want: false},
+		{name: "409 with RetryConflict", policy: Policy{RetryConflict: true}, statusCode: http.StatusConflict, want: true},
+		{name: "net timeout", err: &net.OpError{Op: "read", Net: "tcp", Err: timeoutError{}}, want: true},
+		{name: "ECONNRESET", err: connReset, want: true},
+		{name: "wrapped ECONNRESET", err: fmt.Errorf("failed to read: %w", connReset), want: true},
+		{name: "ECONNREFUSED", err: connRefused, want: true},
+		{name: "unexpected EOF", err: io.ErrUnexpectedEOF, want: true},
+		{name: "context canceled", err: context.Canceled, want: false},
+		{name: "deadline exceeded", err: context.DeadlineExceeded, want: false},
+		{name: "wrapped deadline exceeded", err: fmt.Errorf("request: %w", context.DeadlineExceeded), want: false},
+		{name: "other error", err: io.EOF, want: false},
+
+		{name: "write 429", policy: Policy{Write: true}, statusCode: http.StatusTooManyRequests, want: true},
+		{name: "write 502", policy: Policy{Write: true}, statusCode: http.StatusBadGateway, want: false},
+		{name: "write 503", policy: Policy{Write: true}, statusCode: http.StatusServiceUnavailable, want: false},
+		{name: "write 504", policy: Policy{Write: true}, statusCode: http.StatusGatewayTimeout, want: false},
+		{name: "write 409 with RetryConflict", policy: Policy{Write: true, RetryConflict: true}, statusCode: http.StatusConflict, want: true},
+		{name: "write net timeout", policy: Policy{Write: true}, err: &net.OpError{Op: "read", Net: "tcp", Err: timeoutError{}}, want: false},
+		{name: "write ECONNRESET", policy: Policy{Write: true}, err: connReset, want: false},
+		{name: "write unexpected EOF", policy: Policy{Write: true}, err: io.ErrUnexpectedEOF, want: false},
+		{name: "write ECONNREFUSED", policy: Policy{Write: true}, err: connRefused, want: true},
+		{name: "write dial timeout", policy: Policy{Write: true}, err: &net.OpError{Op: "dial", Net: "tcp", Err: timeoutError{}}, want: true},
+		{name: "write context canceled", policy: Policy{Write: true}, err: context.Canceled, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.IsRetryable(tt.err, tt.statusCode); got != tt.want {
+				t.Errorf("IsRetryable(%v, %d) = %v, want %v", tt.err, tt.statusCode, got, tt.want)
+			}
+		})
+	}
+}
+
+// statusServer answers each request with the next of statuses, repeating the
+// last one, and counts the requests it received
+func statusServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(requests.Add(1))
+		if n > len(statuses) {
+			n = len(statuses)
+		}
+		w.WriteHeader(statuses[n-1])
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+// doPost sends a POST to url with policy and returns the final status
+func doPost(t *testing.T, policy Policy, url string) int {
+	t.Helper()
+
+	resp, err := policy.Do(context.Background(), http.DefaultClient, func() (*http.Request, error) {
+		return http.NewRequest(http.MethodPost, url, nil)
+	})
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestDoWrite(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantStatus   int
+		wantRequests int32
+	}{
+		{name: "5xx is not resent", statuses: []int{http.StatusBadGateway, http.StatusOK}, wantStatus: http.StatusBadGateway, wantRequests: 1},
+		{name: "429 is resent", statuses: []int{http.StatusTooManyRequests, http.StatusOK}, wantStatus: http.StatusOK, wantRequests: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := statusServer(t, tt.statuses...)
+
+			status := doPost(t, Policy{Write: true, BaseDelay: time.Millisecond}, server.URL)
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestDoWriteNotSent(t *testing.T) {
+	// Reserve a port and close it, so connecting is refused
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "http://" + listener.Addr().String()
+	listener.Close()
+
+	var attempts int
+	policy := Policy{Write: true, BaseDelay: time.Millisecond}
+	_, err = policy.Do(context.Background(), http.DefaultClient, func() (*http.Request, error) {
+		attempts++
+		return http.NewRequest(http.MethodPost, url, nil)
+	})
+	if err == nil {
+		t.Fatal("Do succeeded against a closed port")
+	}
+	if attempts != DefaultMaxAttempts {
+		t.Errorf("attempts = %d, want %d", attempts, DefaultMaxAttempts)
+	}
+}
+
+func TestDoBackoff(t *testing.T) {
+	const base = 100 * time.Millisecond
+
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantStatus   int
+		wantRequests int32
+		minElapsed   time.Duration
+		maxElapsed   time.Duration
+	}{
+		{
+			name:         "retries 5xx with exponential backoff",
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			wantStatus:   http.StatusOK,
+			wantRequests: 3,
+			minElapsed:   base + 2*base,
+			maxElapsed:   2 * time.Second,
+		},
+		{
+			name:         "gives up after max attempts",
+			statuses:     []int{http.StatusGatewayTimeout},
+			wantStatus:   http.StatusGatewayTimeout,
+			wantRequests: DefaultMaxAttempts,
+			minElapsed:   base + 2*base,
+			maxElapsed:   2 * time.Second,
+		},
+		{
+			name:         "4xx returns without sleeping",
+			statuses:     []int{http.StatusBadRequest, http.StatusOK},
+			wantStatus:   http.StatusBadRequest,
+			wantRequests: 1,
+			maxElapsed:   base,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := statusServer(t, tt.statuses...)
+
+			start := time.Now()
+			status := doPost(t, Policy{BaseDelay: base}, server.URL)
+			elapsed := time.Since(start)
+
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d", status, tt.wantStatus)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+			if elapsed < tt.minElapsed || elapsed > tt.maxElapsed {
+				t.Errorf("took %s, want between %s and %s", elapsed, tt.minElapsed, tt.maxElapsed)
+			}
+		})
+	}
+}
+
+func TestDoRetryAfter(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	// Retry-After replaces the (here very long) backoff
+	start := time.Now()
+	status := doPost(t, Policy{BaseDelay: time.Minute}, server.URL)
+	if status != http.StatusOK {
+		t.Errorf("status = %d, want %d", status, http.StatusOK)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("took %s, want Retry-After: 0 to retry at once", elapsed)
+	}
+}
+
+func TestWithJitter(t *testing.T) {
+	const d = 100 * time.Millisecond
+	for i := 0; i < 100; i++ {
+		if got := withJitter(d); got < d || got >= d+d/2 {
+			t.Fatalf("withJitter(%s) = %s, want in [%s, %s)", d, got, d, d+d/2)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package transport
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// CABundleEnv names a PEM file of root CAs to trust in addition to the system roots,
+// e.g. for a TLS-inspecting corporate proxy
+const CABundleEnv = "GOTION_CA_BUNDLE"
+
+// ProxyEnv names a proxy URL used for all requests, overriding HTTP_PROXY, HTTPS_PROXY and NO_PROXY
+const ProxyEnv = "GOTION_PROXY"
+
+var (
+	// tlsConfig is the TLS configuration applied by New; nil uses the system defaults
+	tlsConfig *tls.Config
+
+	// proxyURL overrides the proxy from the environment when set
+	proxyURL *url.URL
+)
+
+// ConfigureProxy sets the proxy of transports created by New.
+// An empty proxy uses HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+func ConfigureProxy(proxy string) error {
+	if proxy == "" {
+		proxyURL = nil
+		return nil
+	}
+
+	u, err := url.Parse(proxy)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return fmt.Errorf("invalid proxy URL %q", proxy)
+	}
+	proxyURL = u
+	return nil
+}
+
+// ConfigureTLS sets the TLS configuration of transports created by New.
+// caFile adds the root CAs in a PEM file to the system roots. insecure disables
+// certificate verification and must only be used for testing.
+func ConfigureTLS(caFile string, insecure bool) error {
+	if caFile == "" && !insecure {
+		tlsConfig = nil
+		return nil
+	}
+
+	cfg := &tls.Config{InsecureSkipVerify: insecure}
+
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in CA bundle %s", caFile)
+		}
+		cfg.RootCAs = pool
+	}
+
+	tlsConfig = cfg
+	return nil
+}
+
+// New returns an HTTP transport with the configured proxy and TLS settings.
+// Timeouts and connection pooling are those of http.DefaultTransport.
+func New() http.RoundTripper {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = http.ProxyFromEnvironment
+	if proxyURL != nil {
+		t.Proxy = http.ProxyURL(proxyURL)
+	}
+	if tlsConfig != nil {
+		t.TLSClientConfig = tlsConfig.Clone()
+	}
+	return t
+}
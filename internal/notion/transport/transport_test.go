@@ -0,0 +1,124 @@
+package transport
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// resetConfig restores the default transport settings when the test ends
+func resetConfig(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		tlsConfig = nil
+		proxyURL = nil
+	})
+}
+
+// writeCABundle writes the certificate of server to a PEM file and returns its path
+func writeCABundle(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigureTLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+	bundle := writeCABundle(t, server)
+
+	tests := []struct {
+		name     string
+		caFile   string
+		insecure bool
+		wantErr  bool
+	}{
+		{name: "system roots reject the server", wantErr: true},
+		{name: "CA bundle trusts the server", caFile: bundle},
+		{name: "insecure skips verification", insecure: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resetConfig(t)
+			if err := ConfigureTLS(tt.caFile, tt.insecure); err != nil {
+				t.Fatalf("ConfigureTLS: %v", err)
+			}
+
+			client := &http.Client{Transport: New()}
+			resp, err := client.Get(server.URL)
+			if err == nil {
+				resp.Body.Close()
+			}
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Get error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigureTLSInvalidBundle(t *testing.T) {
+	resetConfig(t)
+
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(empty, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, caFile := range []string{empty, filepath.Join(t.TempDir(), "missing.pem")} {
+		if err := ConfigureTLS(caFile, false); err == nil {
+			t.Errorf("ConfigureTLS(%s) succeeded, want an error", caFile)
+		}
+	}
+}
+
+func TestConfigureProxy(t *testing.T) {
+	resetConfig(t)
+
+	// A plain HTTP proxy receives the request with the absolute target URL
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+	}))
+	t.Cleanup(proxy.Close)
+
+	if err := ConfigureProxy(proxy.URL); err != nil {
+		t.Fatalf("ConfigureProxy: %v", err)
+	}
+
+	client := &http.Client{Transport: New()}
+	resp, err := client.Get("http://api.notion.invalid/v1/users/me")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	resp.Body.Close()
+
+	if want := "http://api.notion.invalid/v1/users/me"; proxied != want {
+		t.Errorf("proxy received %q, want %q", proxied, want)
+	}
+}
+
+func TestConfigureProxyInvalid(t *testing.T) {
+	resetConfig(t)
+
+	for _, proxy := range []string{"proxy.example.com:8080", "http://", "://bad"} {
+		if err := ConfigureProxy(proxy); err == nil {
+			t.Errorf("ConfigureProxy(%q) succeeded, want an error", proxy)
+		}
+	}
+
+	if err := ConfigureProxy(""); err != nil {
+		t.Errorf("ConfigureProxy(\"\") = %v, want nil", err)
+	}
+	if proxyURL != nil {
+		t.Errorf("proxyURL = %v after clearing, want nil", proxyURL)
+	}
+}
@@ -1,6 +1,52 @@
 package types
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrUnsupported is matched by errors returned for operations a backend does not support
+var ErrUnsupported = errors.New("operation not supported by this backend")
+
+// UnsupportedError returns an error with the given message that matches ErrUnsupported
+func UnsupportedError(msg string) error {
+	return &unsupportedError{msg: msg}
+}
+
+type unsupportedError struct {
+	msg string
+}
+
+func (e *unsupportedError) Error() string { return e.msg }
+
+func (e *unsupportedError) Unwrap() error { return ErrUnsupported }
+
+type dryRunKey struct{}
+
+// WithDryRun returns a context in which write operations print the request
+// they would send to w instead of sending it
+func WithDryRun(ctx context.Context, w io.Writer) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, w)
+}
+
+// DryRunWriter returns the writer set by WithDryRun, or nil if ctx is not a dry run
+func DryRunWriter(ctx context.Context) io.Writer {
+	w, _ := ctx.Value(dryRunKey{}).(io.Writer)
+	return w
+}
+
+// WriteDryRun prints a request that was not sent: a line naming it, then the indented JSON body
+func WriteDryRun(w io.Writer, request string, body interface{}) error {
+	data, err := json.MarshalIndent(body, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+	_, err = fmt.Fprintf(w, "%s\n%s\n", request, data)
+	return err
+}
 
 // Client defines the interface for Notion API operations
 type Client interface {
@@ -16,6 +62,27 @@ type Client interface {
 	// UpdatePage updates an existing page
 	UpdatePage(ctx context.Context, pageID string, opts *UpdatePageOptions) (*UpdatePageResult, error)
 
+	// ArchivePage archives (moves to trash) an existing page
+	ArchivePage(ctx context.Context, pageID string) error
+
+	// GetDatabase retrieves a database's title and property schema
+	GetDatabase(ctx context.Context, databaseID string) (*Database, error)
+
+	// QueryDatabase queries all rows of a database
+	QueryDatabase(ctx context.Context, databaseID string, opts *QueryDatabaseOptions) (*QueryDatabaseResult, error)
+
+	// ListComments lists all comments on a page
+	ListComments(ctx context.Context, pageID string) ([]Comment, error)
+
+	// CreateComment adds a comment to a page
+	CreateComment(ctx context.Context, pageID string, text string) (*Comment, error)
+
+	// AppendBlocks appends block objects to the children of a page or block
+	AppendBlocks(ctx context.Context, blockID string, blocks []map[string]interface{}) error
+
+	// DeleteBlock deletes (archives) a block
+	DeleteBlock(ctx context.Context, blockID string) (*Block, error)
+
 	// FormatPage formats a page result as JSON string
 	FormatPage(result *PageResult) (string, error)
 
@@ -26,24 +93,50 @@ type Client interface {
 // GetPageOptions contains options for GetPage
 type GetPageOptions struct {
 	FilterProperties []string
+	SkipChildren     bool       // Fetch page metadata only, without block children; RawJSON is then the page object (API only)
+	PropertyTypes    []string   // Keep only properties of these types, e.g. "date" (API only)
+	ResolveUsers     bool       // Look up missing user names in people properties (API only)
+	BlockCache       BlockCache // Reuse block children while the page is unchanged (API only)
+	BlockConcurrency int        // Concurrent block children requests; zero uses the default (API only)
+}
+
+// BlockCache stores the block children of pages. An entry is valid only
+// while the page's last_edited_time is unchanged.
+type BlockCache interface {
+	// LoadBlocks returns the cached blocks of a page last edited at lastEditedTime
+	LoadBlocks(pageID, lastEditedTime string) ([]json.RawMessage, bool)
+
+	// SaveBlocks stores the page object and blocks of a page last edited at lastEditedTime
+	SaveBlocks(pageID, lastEditedTime string, page json.RawMessage, blocks []json.RawMessage) error
 }
 
 // SearchOptions contains options for Search
 type SearchOptions struct {
 	PageSize    int
 	StartCursor string
-	Sort        string // "ascending" or "descending"
+	Sort        string // "ascending" or "descending", by last_edited_time
+	ObjectType  string // "page", "database", or "all" (default: "page")
 }
 
 // PageResult represents the result of GetPage
 type PageResult struct {
-	ID      string
-	Title   string
-	URL     string
-	Content string            // Markdown content
-	RawJSON []byte            // Raw JSON (API only)
-	Props   map[string]string // Properties
-	Source  string            // "api" or "mcp"
+	ID             string
+	Title          string
+	URL            string
+	PublicURL      string            // Public URL if published to the web, else empty (API only)
+	LastEditedTime string            // RFC 3339 timestamp (API only)
+	Content        string            // Markdown content
+	RawJSON        []byte            // Raw JSON (API only)
+	Props          map[string]string // Properties
+	ChildPages     []ChildPage       // Child pages and databases (API only)
+	Source         string            // "api" or "mcp"
+}
+
+// ChildPage represents a child_page or child_database block of a page
+type ChildPage struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"` // "child_page" or "child_database"
+	Title string `json:"title"`
 }
 
 // SearchResult represents the result of Search
@@ -56,16 +149,56 @@ type SearchResult struct {
 	Source     string // "api" or "mcp"
 }
 
+// SearchList is the JSON envelope printed for search results by both backends.
+// next_cursor is null when there are no more results. The MCP backend has no
+// structured results and puts the tool response in content instead.
+type SearchList struct {
+	Object     string            `json:"object"`
+	Results    []json.RawMessage `json:"results"`
+	NextCursor *string           `json:"next_cursor"`
+	HasMore    bool              `json:"has_more"`
+	Content    json.RawMessage   `json:"content,omitempty"`
+}
+
+// NewSearchList returns an empty list envelope for a search result's cursor
+func NewSearchList(result *SearchResult) *SearchList {
+	list := &SearchList{
+		Object:  "list",
+		Results: []json.RawMessage{},
+		HasMore: result.HasMore,
+	}
+	if result.NextCursor != "" {
+		cursor := result.NextCursor
+		list.NextCursor = &cursor
+	}
+	return list
+}
+
+// Format returns the envelope as indented JSON
+func (l *SearchList) Format() (string, error) {
+	output, err := json.MarshalIndent(l, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal search results: %w", err)
+	}
+	return string(output) + "\n", nil
+}
+
 // PageSummary represents a summary of a page in search results
 type PageSummary struct {
-	ID    string
-	Title string
-	URL   string
+	ID             string
+	Object         string // "page" or "database"
+	Title          string
+	URL            string
+	LastEditedTime string          // RFC 3339 timestamp (API only)
+	CreatedTime    string          // RFC 3339 timestamp (API only)
+	Parent         *Parent         // Page, database or data source containing the result (API only)
+	Properties     json.RawMessage // Notion property values of a page, or the schema of a database (API only)
+	Archived       bool            // Archived or in the trash (API only)
 }
 
 // Parent represents the parent of a page
 type Parent struct {
-	Type string // "page_id", "database_id", "data_source_id"
+	Type string // "page_id", "database_id", "data_source_id"; search results may also have "block_id" or "workspace"
 	ID   string
 }
 
@@ -93,3 +226,48 @@ type UpdatePageResult struct {
 	RawJSON []byte
 	Source  string
 }
+
+// Database represents a database's title and property schema
+type Database struct {
+	ID         string
+	Title      string
+	Properties map[string]string   // Property name -> property type
+	Options    map[string][]string // Allowed option names of select, multi_select and status properties
+}
+
+// QueryDatabaseOptions contains options for QueryDatabase
+type QueryDatabaseOptions struct {
+	Filter json.RawMessage // Notion filter object, passed through as-is
+}
+
+// QueryDatabaseResult represents the result of QueryDatabase
+type QueryDatabaseResult struct {
+	Rows    []DatabaseRow
+	RawJSON []byte // Raw JSON array of all rows (API only)
+	Source  string // "api" or "mcp"
+}
+
+// DatabaseRow represents a page (row) in a database
+type DatabaseRow struct {
+	ID             string
+	Title          string
+	URL            string
+	LastEditedTime string              // RFC 3339 timestamp
+	Props          map[string]string   // Property values as plain text
+	Options        map[string][]string // Selected option names of select, status and multi_select properties
+}
+
+// Block represents a content block
+type Block struct {
+	ID       string
+	Type     string
+	Archived bool
+}
+
+// Comment represents a comment on a page
+type Comment struct {
+	ID          string
+	AuthorID    string
+	CreatedTime string
+	Text        string
+}
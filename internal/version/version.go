@@ -23,3 +23,8 @@ func Info() string {
 func Short() string {
 	return Version
 }
+
+// UserAgent returns the User-Agent header sent with HTTP requests
+func UserAgent() string {
+	return fmt.Sprintf("gotion/%s (%s/%s)", Version, runtime.GOOS, runtime.GOARCH)
+}